@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 	"zion/config"
+	"zion/i18n"
+	"zion/languages"
 	"zion/plugins"
+	"zion/prompts"
 )
 
 type GeminiRequest struct {
@@ -19,6 +21,7 @@ type GeminiRequest struct {
 
 type Content struct {
 	Parts []Part `json:"parts"`
+	Role  string `json:"role,omitempty"`
 }
 
 type Part struct {
@@ -32,7 +35,9 @@ type GeminiResponse struct {
 				Text string `json:"text"`
 			} `json:"parts"`
 		} `json:"content"`
+		FinishReason string `json:"finishReason"`
 	} `json:"candidates"`
+	UsageMetadata UsageMetadata `json:"usageMetadata"`
 }
 
 type ScaffoldResponse struct {
@@ -42,25 +47,36 @@ type ScaffoldResponse struct {
 	} `json:"structure"`
 }
 
-func callGeminiAPI(prompt string) (string, error) {
-	cfg := config.LoadConfig()
-	if cfg.GeminiAPIKey == "" {
-		return "", fmt.Errorf("GEMINI_API_KEY não configurada")
-	}
+// rawScaffoldResponse é a resposta do modelo com o valor de cada arquivo ainda sem
+// decodificar, para que o conteúdo de arquivos JSON mantenha a ordem das chaves
+type rawScaffoldResponse struct {
+	Structure struct {
+		Directories []string                   `json:"directories"`
+		Files       map[string]json.RawMessage `json:"files"`
+	} `json:"structure"`
+}
 
-	fmt.Println("🔑 Usando chave da API configurada")
+// geminiEndpoint monta a URL de um método da API Gemini para o modelo configurado
+func geminiEndpoint(cfg *config.Config, method string) string {
+	return fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:%s?key=%s", cfg.Model, method, cfg.GeminiAPIKey)
+}
 
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent?key=%s", cfg.GeminiAPIKey)
+// buildGeminiRequest monta o corpo da requisição enviada à API Gemini
+func buildGeminiRequest(prompt string) map[string]interface{} {
+	return buildGeminiContentsRequest(userContents(prompt))
+}
 
-	request := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{"text": prompt},
-				},
-				"role": "user",
-			},
-		},
+// userContents monta uma conversa com uma única mensagem do usuário
+func userContents(prompt string) []Content {
+	return []Content{
+		{Parts: []Part{{Text: prompt}}, Role: "user"},
+	}
+}
+
+// buildGeminiContentsRequest monta o corpo da requisição a partir de uma conversa com vários turnos
+func buildGeminiContentsRequest(contents []Content) map[string]interface{} {
+	return map[string]interface{}{
+		"contents": contents,
 		"safetySettings": []map[string]interface{}{
 			{
 				"category":  "HARM_CATEGORY_DANGEROUS_CONTENT",
@@ -68,77 +84,134 @@ func callGeminiAPI(prompt string) (string, error) {
 			},
 		},
 	}
+}
 
-	jsonData, err := json.Marshal(request)
+// geminiResult é o resultado de uma chamada à API Gemini
+type geminiResult struct {
+	Text         string
+	FinishReason string
+	// Truncated indica que a resposta foi interrompida por MAX_TOKENS em algum momento
+	Truncated bool
+	Usage     UsageMetadata
+}
+
+func callGeminiAPI(prompt string) (string, error) {
+	result, err := requestGemini(prompt)
+	if err != nil {
+		return "", err
+	}
+	return cleanResponseJSON(result.Text)
+}
+
+// requestGemini envia o prompt, completa respostas truncadas e retorna o texto bruto
+func requestGemini(prompt string) (*geminiResult, error) {
+	fmt.Print(i18n.T("ai.using_key"))
+	fmt.Print(i18n.T("ai.sending"))
+
+	result, err := sendGeminiRequest(buildGeminiRequest(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Print(i18n.T("ai.received"))
+
+	result, err = continueTruncatedResponse(userContents(prompt), result, nil)
 	if err != nil {
-		return "", fmt.Errorf("erro ao criar request: %v", err)
+		return nil, err
 	}
 
-	fmt.Println("📡 Enviando requisição para a API Gemini...")
+	fmt.Print(i18n.T("ai.processing"))
+	return result, nil
+}
+
+// sendGeminiRequest envia uma requisição generateContent e retorna o texto do primeiro candidato
+func sendGeminiRequest(request map[string]interface{}) (*geminiResult, error) {
+	cfg := config.LoadConfig()
+	if cached, ok := loadCachedResult(cfg, request); ok {
+		return cached, nil
+	}
+	if cfg.GeminiAPIKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY não configurada")
+	}
+
+	url := geminiEndpoint(cfg, "generateContent")
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar request: %v", err)
+	}
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("erro na chamada API: %v", err)
+		return nil, fmt.Errorf("erro na chamada API: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("erro ao ler resposta: %v", err)
+		return nil, fmt.Errorf("erro ao ler resposta: %v", err)
 	}
 
-	fmt.Println("📥 Resposta recebida da API")
-
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API retornou status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API retornou status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var geminiResp GeminiResponse
 	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", fmt.Errorf("erro ao processar resposta: %v\nBody: %s", err, string(body))
+		return nil, fmt.Errorf("erro ao processar resposta: %v\nBody: %s", err, string(body))
 	}
 
+	// O uso é contabilizado mesmo quando a resposta vem sem conteúdo
+	recordUsage(cfg, geminiResp.UsageMetadata)
+
 	if len(geminiResp.Candidates) == 0 {
-		return "", fmt.Errorf("nenhuma resposta gerada da API")
+		return nil, fmt.Errorf("nenhuma resposta gerada da API")
 	}
 
 	if len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("resposta sem conteúdo")
+		return nil, fmt.Errorf("resposta sem conteúdo")
 	}
 
-	fmt.Println("🔍 Processando resposta...")
-
-	// Extrai o JSON da resposta que pode estar dentro de um bloco markdown
-	responseText := geminiResp.Candidates[0].Content.Parts[0].Text
+	result := &geminiResult{
+		Text:         geminiResp.Candidates[0].Content.Parts[0].Text,
+		FinishReason: geminiResp.Candidates[0].FinishReason,
+		Usage:        geminiResp.UsageMetadata,
+	}
+	storeCachedResult(cfg, request, result)
+	return result, nil
+}
 
+// cleanResponseJSON extrai o JSON do texto retornado pelo modelo, removendo blocos
+// markdown e aplicando a limpeza quando o JSON não é válido
+func cleanResponseJSON(responseText string) (string, error) {
 	// Remove blocos de código markdown se presentes
 	if strings.HasPrefix(responseText, "```json\n") && strings.HasSuffix(responseText, "\n```") {
-		fmt.Println("📝 Removendo blocos de código markdown")
+		fmt.Print(i18n.T("ai.json.strip_fence"))
 		responseText = strings.TrimPrefix(responseText, "```json\n")
 		responseText = strings.TrimSuffix(responseText, "\n```")
 	}
 
-	fmt.Println("🧹 Limpando e corrigindo JSON...")
+	fmt.Print(i18n.T("ai.json.cleaning"))
 
 	// Pré-processamento do JSON para lidar com caracteres especiais em nomes de pacotes
 	var jsonMap map[string]interface{}
 	if err := json.Unmarshal([]byte(responseText), &jsonMap); err != nil {
-		fmt.Printf("⚠️  JSON inválido, tentando limpar: %v\n", err)
+		fmt.Print(i18n.T("ai.json.invalid", err))
 
 		// Se falhar, tenta limpar o JSON
 		cleanedResponse := cleanJSONString(responseText)
 
-		fmt.Println("🔄 Tentando parse do JSON limpo...")
+		fmt.Print(i18n.T("ai.json.retry"))
 
 		if err := json.Unmarshal([]byte(cleanedResponse), &jsonMap); err != nil {
 			return "", fmt.Errorf("resposta não é um JSON válido mesmo após limpeza: %v\nResposta original:\n%s\n\nResposta limpa:\n%s", err, responseText, cleanedResponse)
 		}
 
-		fmt.Println("✅ JSON limpo e válido")
+		fmt.Print(i18n.T("ai.json.cleaned_valid"))
 		responseText = cleanedResponse
 	} else {
-		fmt.Println("✅ JSON válido")
+		fmt.Print(i18n.T("ai.json.valid"))
 	}
 
 	return responseText, nil
@@ -146,36 +219,19 @@ func callGeminiAPI(prompt string) (string, error) {
 
 // cleanJSONString limpa e corrige problemas comuns em strings JSON
 func cleanJSONString(input string) string {
-	fmt.Println("🧰 Iniciando limpeza do JSON")
+	fmt.Print(i18n.T("ai.json.clean_start"))
 
 	// Remove caracteres invisíveis e espaços em branco extras
 	input = strings.TrimSpace(input)
-	fmt.Println("✂️  Removidos espaços em branco extras")
-
-	// Pré-processa as dependências
-	input = preprocessDependencies(input)
-	fmt.Println("📦 Pré-processadas as dependências")
+	fmt.Print(i18n.T("ai.json.trimmed"))
 
 	// Corrige aspas dentro de strings
 	input = fixQuotesInJSON(input)
-	fmt.Println("🔧 Corrigidas aspas em strings")
+	fmt.Print(i18n.T("ai.json.quotes"))
 
 	return input
 }
 
-// preprocessDependencies faz um pré-processamento específico nas seções de dependências
-func preprocessDependencies(input string) string {
-	// Regex para encontrar blocos de dependencies e devDependencies
-	depsRegex := regexp.MustCompile(`"(dev)?dependencies"\s*:\s*{([^}]+)}`)
-
-	return depsRegex.ReplaceAllStringFunc(input, func(match string) string {
-		// Processa cada pacote dentro do bloco de dependências
-		packageRegex := regexp.MustCompile(`"(@[^"]+)"\s*:\s*"([^"]+)"`)
-		processed := packageRegex.ReplaceAllString(match, `"\\u0040$1": "$2"`)
-		return processed
-	})
-}
-
 // fixQuotesInJSON corrige problemas com aspas em strings JSON
 func fixQuotesInJSON(input string) string {
 	var result strings.Builder
@@ -202,34 +258,29 @@ func fixQuotesInJSON(input string) string {
 	return result.String()
 }
 
-// fixNpmPackageNames corrige problemas com nomes de pacotes npm que contêm @
-func fixNpmPackageNames(input string) string {
-	// Regex para encontrar nomes de pacotes npm com @
-	npmPackageRegex := regexp.MustCompile(`"(@[^"]+)":\s*"([^"]+)"`)
-
-	// Substitui mantendo a estrutura correta
-	fixed := npmPackageRegex.ReplaceAllStringFunc(input, func(match string) string {
-		// Extrai o nome do pacote e a versão
-		parts := npmPackageRegex.FindStringSubmatch(match)
-		if len(parts) != 3 {
-			return match
-		}
-
-		// Reconstrói com escape adequado
-		packageName := strings.ReplaceAll(parts[1], "@", "\\u0040")
-		return fmt.Sprintf(`"%s": "%s"`, packageName, parts[2])
-	})
-
-	// Se houve substituições, registra no log
-	if fixed != input {
-		fmt.Println("📝 Corrigidos pacotes npm com @")
-	}
-
-	return fixed
+// ScaffoldOptions controla como a estrutura do projeto é obtida da API
+type ScaffoldOptions struct {
+	// Stream usa streamGenerateContent e anuncia cada arquivo assim que ele fica completo
+	Stream bool
+	// OnFile é chamado para cada arquivo completo recebido durante o streaming
+	OnFile func(file StreamedFile)
+	// OnProgress é chamado a cada trecho recebido durante o streaming
+	OnProgress func(progress StreamProgress)
+	// OnPrompt recebe o prompt final enviado à API, após os hooks ModifyPrompt
+	OnPrompt func(prompt string)
+	// Framework é o framework do pacote de linguagem a usar, se houver
+	Framework string
+	// Context, se informado, inclui no prompt um projeto existente como referência
+	Context *ProjectContext
 }
 
 // GenerateProjectScaffolding gera uma estrutura de projeto com base na linguagem, nome e descrição fornecidos
 func GenerateProjectScaffolding(language, projectName, description string, registeredPlugins []string) (string, error) {
+	return GenerateProjectScaffoldingWithOptions(language, projectName, description, registeredPlugins, ScaffoldOptions{})
+}
+
+// GenerateProjectScaffoldingWithOptions gera a estrutura do projeto usando as opções informadas
+func GenerateProjectScaffoldingWithOptions(language, projectName, description string, registeredPlugins []string, opts ScaffoldOptions) (string, error) {
 	// Substituir SamplePlugin por CorePlugin em registeredPlugins
 	for i, plugin := range registeredPlugins {
 		if plugin == "SamplePlugin" {
@@ -247,124 +298,48 @@ func GenerateProjectScaffolding(language, projectName, description string, regis
 	// Executar o hook BeforeGeneration para todos os plugins
 	ctx = plugins.ExecuteHook(plugins.BeforeGeneration, ctx)
 
-	// Construir a descrição do projeto com mais detalhes e boas práticas
-	projectDesc := fmt.Sprintf(`Você é um especialista em desenvolvimento de software com vasta experiência em %s.
-Crie uma estrutura moderna e profissional para um projeto chamado '%s'.
-
-O projeto deve seguir:
-1. Arquitetura limpa e modular
-2. Padrões de projeto adequados à linguagem %s
-3. Estrutura de diretórios organizada e escalável
-4. Configuração de ambiente flexível
-5. Documentação clara e objetiva`, language, projectName, language)
-
-	// Adicionar descrição específica se fornecida
-	if description != "" {
-		projectDesc += fmt.Sprintf(`\n\nRequisitos específicos:\n%s`, description)
-	}
-
-	// Adicionar requisitos específicos por linguagem
-	switch strings.ToLower(language) {
-	case "js", "javascript":
-		projectDesc += `\n\nRequisitos específicos para JavaScript:
-1. Estrutura moderna com ES6+
-2. Sistema de módulos ES
-3. Configuração de linting (ESLint)
-4. Configuração de formatação (Prettier)
-5. Scripts NPM úteis
-6. Testes unitários configurados
-7. Documentação com JSDoc`
-
-	case "ts", "typescript":
-		projectDesc += `\n\nRequisitos específicos para TypeScript:
-1. Configuração do TSConfig otimizada
-2. Tipos bem definidos
-3. Estrutura de módulos organizada
-4. Configuração de linting (ESLint)
-5. Configuração de formatação (Prettier)
-6. Scripts NPM úteis
-7. Testes unitários com Jest/Vitest
-8. Documentação com TSDoc`
-
-	case "go", "golang":
-		projectDesc += `\n\nRequisitos específicos para Go:
-1. Estrutura de módulos Go
-2. Padrões idiomáticos Go
-3. Configuração de linting (golangci-lint)
-4. Makefile com comandos úteis
-5. Testes unitários
-6. Documentação no estilo Go
-7. Gerenciamento de dependências com go.mod`
-
-	case "rs", "rust":
-		projectDesc += `\n\nRequisitos específicos para Rust:
-1. Estrutura de workspace Cargo
-2. Módulos bem organizados
-3. Tratamento de erros robusto
-4. Configuração de linting (clippy)
-5. Testes unitários e de integração
-6. Documentação com rustdoc
-7. CI/CD com cargo`
-
-	case "cs", "csharp":
-		projectDesc += `\n\nRequisitos específicos para C#:
-1. Estrutura de solução .NET moderna
-2. Organização em camadas (DDD/Clean Architecture)
-3. Configuração de linting
-4. Testes com xUnit/NUnit
-5. Documentação XML
-6. Scripts de build
-7. Gerenciamento de dependências com NuGet`
-	}
-
-	prompt := fmt.Sprintf(`%s
-
-IMPORTANTE: Para garantir um JSON válido, siga estas regras:
-
-1. Use apenas aspas duplas (") para strings
-2. Para valores de arquivos JSON (como package.json), use a seguinte sintaxe:
-   "arquivo.json": {
-     "content": {
-       // conteúdo do JSON aqui
-     }
-   }
-3. Para outros arquivos de texto, use a seguinte sintaxe:
-   "arquivo.txt": {
-     "content": "conteúdo do arquivo"
-   }
-4. Para nomes de pacotes npm que começam com @, use a seguinte sintaxe:
-   "dependencies": {
-     "pkg:@types/node": "^20.4.8",
-     "pkg:@typescript-eslint/parser": "^6.7.5"
-   }
-
-Retorne um JSON com esta estrutura exata:
-{
-  "structure": {
-    "directories": ["dir1", "dir2"],
-    "files": {
-      "arquivo.json": {
-        "content": {
-          // conteúdo JSON aqui
-        }
-      },
-      "arquivo.txt": {
-        "content": "conteúdo texto aqui"
-      }
-    }
-  }
-}`, projectDesc)
+	prompt, err := buildScaffoldPrompt(language, opts.Framework, projectName, description)
+	if err != nil {
+		return "", err
+	}
+	if opts.Context != nil {
+		reference, err := renderPrompt("reference.tmpl", prompts.Data{ProjectContext: opts.Context.Prompt()})
+		if err != nil {
+			return "", err
+		}
+		prompt += "\n\n" + reference
+	}
 
 	// Executar o hook ModifyPrompt para todos os plugins
 	ctx.Prompt = prompt
 	ctx = plugins.ExecuteHook(plugins.ModifyPrompt, ctx)
 	prompt = ctx.Prompt
+	if opts.OnPrompt != nil {
+		opts.OnPrompt(prompt)
+	}
 
-	response, err := callGeminiAPI(prompt)
+	var result *geminiResult
+	if opts.Stream {
+		result, err = streamScaffold(prompt, opts)
+	} else {
+		result, err = requestGemini(prompt)
+	}
 	if err != nil {
 		return "", err
 	}
 
+	response, err := cleanResponseJSON(result.Text)
+	if err != nil {
+		if !result.Truncated {
+			return "", err
+		}
+		// Mesmo após as continuações o JSON segue incompleto: regenera os arquivos faltantes
+		response, err = recoverTruncatedManifest(language, opts.Framework, projectName, description, result.Text)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Atualizar a resposta no contexto
 	ctx.Response = response
 
@@ -386,15 +361,106 @@ Retorne um JSON com esta estrutura exata:
 	return response, nil
 }
 
+// docLanguage é o idioma pedido para a documentação e os comentários do projeto gerado
+var docLanguage string
+
+// SetDocLanguage define o idioma da documentação e dos comentários gerados;
+// vazio usa o idioma das mensagens da CLI
+func SetDocLanguage(lang string) {
+	docLanguage = lang
+}
+
+// DocLanguage retorna o código do idioma da documentação gerada
+func DocLanguage() string {
+	if docLanguage != "" {
+		return docLanguage
+	}
+	return i18n.Language()
+}
+
+// promptData monta os dados dos templates de prompt, com os trechos do pacote da
+// linguagem e do framework escolhido, se houver
+func promptData(language, framework, projectName, description string) (prompts.Data, error) {
+	data := prompts.Data{
+		Language:    language,
+		ProjectName: projectName,
+		Description: description,
+		DocLanguage: i18n.LanguageName(DocLanguage()),
+	}
+
+	registry, err := languages.Load(".")
+	if err != nil {
+		return data, err
+	}
+	pack := registry.Find(language)
+	if pack == nil {
+		if framework != "" {
+			return data, fmt.Errorf("linguagem '%s' não possui pacote de linguagem; não é possível usar o framework '%s'", language, framework)
+		}
+		return data, nil
+	}
+
+	data.Language = pack.DisplayName
+	data.LanguageRequirements = pack.Prompt
+	if framework != "" {
+		fw, err := pack.Framework(framework)
+		if err != nil {
+			return data, err
+		}
+		data.Framework = fw.DisplayName
+		data.FrameworkRequirements = fw.Prompt
+	}
+	return data, nil
+}
+
+// buildProjectDescription descreve o projeto, as boas práticas e os requisitos da linguagem
+// e do framework a partir dos templates de prompt e dos pacotes de linguagem
+func buildProjectDescription(language, framework, projectName, description string) (string, error) {
+	data, err := promptData(language, framework, projectName, description)
+	if err != nil {
+		return "", err
+	}
+	set, err := prompts.Load(".")
+	if err != nil {
+		return "", err
+	}
+	return set.ProjectDescription(data)
+}
+
+// renderPrompt renderiza um template de prompt com as substituições de ~/.zion/prompts
+// e de .zion/prompts no diretório atual
+func renderPrompt(name string, data prompts.Data) (string, error) {
+	set, err := prompts.Load(".")
+	if err != nil {
+		return "", err
+	}
+	return set.Render(name, data)
+}
+
+// ScaffoldPrompt retorna o prompt de geração do scaffold renderizado a partir dos
+// templates, sem as alterações feitas por plugins
+func ScaffoldPrompt(language, framework, projectName, description string) (string, error) {
+	return buildScaffoldPrompt(language, framework, projectName, description)
+}
+
+// buildScaffoldPrompt monta o prompt de geração do scaffold antes dos hooks de plugins
+func buildScaffoldPrompt(language, framework, projectName, description string) (string, error) {
+	data, err := promptData(language, framework, projectName, description)
+	if err != nil {
+		return "", err
+	}
+	set, err := prompts.Load(".")
+	if err != nil {
+		return "", err
+	}
+	return set.Scaffold(data)
+}
+
 // processScaffoldResponse processa a resposta do scaffold para garantir JSON válido
 func processScaffoldResponse(response string) (string, error) {
-	// Primeiro, vamos tentar fazer parse do JSON base
-	var baseStruct struct {
-		Structure struct {
-			Directories []string               `json:"directories"`
-			Files       map[string]interface{} `json:"files"`
-		} `json:"structure"`
-	}
+	// Primeiro, vamos tentar fazer parse do JSON base. O valor de cada arquivo fica como
+	// json.RawMessage, que é gravado de volta sem reordenar as chaves
+	var baseStruct rawScaffoldResponse
 
 	if err := json.Unmarshal([]byte(response), &baseStruct); err != nil {
 		return "", fmt.Errorf("erro no parse inicial: %v", err)
@@ -402,51 +468,28 @@ func processScaffoldResponse(response string) (string, error) {
 
 	// Processar cada arquivo
 	for filename, fileContent := range baseStruct.Structure.Files {
-		if contentMap, ok := fileContent.(map[string]interface{}); ok {
+		var contentMap map[string]json.RawMessage
+		if err := json.Unmarshal(fileContent, &contentMap); err == nil {
+			// Entradas tipadas (base64, url, modo do arquivo...) são mantidas como estão
+			if _, typed := typedEntry(fileContent); typed {
+				continue
+			}
 			if content, exists := contentMap["content"]; exists {
-				// Se o conteúdo for um objeto JSON
-				if contentObj, isObj := content.(map[string]interface{}); isObj {
-					// Processar pacotes npm se for package.json
-					if filename == "package.json" {
-						if deps, hasDeps := contentObj["dependencies"].(map[string]interface{}); hasDeps {
-							processedDeps := make(map[string]interface{})
-							for key, value := range deps {
-								if strings.HasPrefix(key, "pkg:@") {
-									newKey := "@" + strings.TrimPrefix(key, "pkg:@")
-									processedDeps[newKey] = value
-								} else {
-									processedDeps[key] = value
-								}
-							}
-							contentObj["dependencies"] = processedDeps
-						}
-						if devDeps, hasDevDeps := contentObj["devDependencies"].(map[string]interface{}); hasDevDeps {
-							processedDevDeps := make(map[string]interface{})
-							for key, value := range devDeps {
-								if strings.HasPrefix(key, "pkg:@") {
-									newKey := "@" + strings.TrimPrefix(key, "pkg:@")
-									processedDevDeps[newKey] = value
-								} else {
-									processedDevDeps[key] = value
-								}
-							}
-							contentObj["devDependencies"] = processedDevDeps
-						}
-					}
-					baseStruct.Structure.Files[filename] = contentObj
-				} else {
-					// Se for conteúdo de texto simples
-					baseStruct.Structure.Files[filename] = content
-				}
+				// O conteúdo, texto ou objeto JSON, substitui o envelope {"content": ...}
+				baseStruct.Structure.Files[filename] = content
 			}
 		}
 	}
 
-	// Converter de volta para JSON
-	result, err := json.MarshalIndent(baseStruct, "", "  ")
+	// Converter de volta para JSON, sem escapar <, > e & do conteúdo
+	compact, err := marshalJSONValue(baseStruct)
 	if err != nil {
 		return "", fmt.Errorf("erro ao gerar JSON final: %v", err)
 	}
+	var result bytes.Buffer
+	if err := json.Indent(&result, compact, "", "  "); err != nil {
+		return "", fmt.Errorf("erro ao gerar JSON final: %v", err)
+	}
 
-	return string(result), nil
+	return result.String(), nil
 }
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zion/config"
+	"zion/i18n"
+)
+
+// cacheProvider identifica o provedor na chave do cache
+const cacheProvider = "gemini"
+
+// cacheEntry é uma resposta armazenada no cache em disco
+type cacheEntry struct {
+	CreatedAt    time.Time `json:"created_at"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	Text         string    `json:"text"`
+	FinishReason string    `json:"finish_reason"`
+}
+
+// cacheEnabled permite desligar o cache para a execução atual (--no-cache)
+var cacheEnabled = true
+
+// SetCacheEnabled liga ou desliga o uso do cache de respostas
+func SetCacheEnabled(enabled bool) {
+	cacheEnabled = enabled
+}
+
+// cacheKey calcula a chave do cache a partir do provedor, do modelo e da conversa
+// enviada, que para uma única mensagem é o prompt final após os hooks ModifyPrompt
+func cacheKey(cfg *config.Config, contents []Content) string {
+	h := sha256.New()
+	h.Write([]byte(cacheProvider + "\x00" + cfg.Model + "\x00"))
+	for _, content := range contents {
+		h.Write([]byte(content.Role + "\x00"))
+		for _, part := range content.Parts {
+			h.Write([]byte(part.Text + "\x00"))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestContents extrai a conversa de uma requisição montada por buildGeminiContentsRequest
+func requestContents(request map[string]interface{}) []Content {
+	contents, _ := request["contents"].([]Content)
+	return contents
+}
+
+// loadCachedResult retorna a resposta em cache para a requisição, se existir e estiver válida
+func loadCachedResult(cfg *config.Config, request map[string]interface{}) (*geminiResult, bool) {
+	if !cacheEnabled || cfg.CacheTTL <= 0 {
+		return nil, false
+	}
+
+	path := filepath.Join(cfg.CacheDir, cacheKey(cfg, requestContents(request))+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.CreatedAt) > cfg.CacheTTL {
+		os.Remove(path)
+		return nil, false
+	}
+
+	fmt.Print(i18n.T("ai.cache.hit"))
+	return &geminiResult{Text: entry.Text, FinishReason: entry.FinishReason}, true
+}
+
+// storeCachedResult grava a resposta no cache; falhas são apenas avisadas
+func storeCachedResult(cfg *config.Config, request map[string]interface{}, result *geminiResult) {
+	if !cacheEnabled || cfg.CacheTTL <= 0 {
+		return
+	}
+
+	entry := cacheEntry{
+		CreatedAt:    time.Now(),
+		Provider:     cacheProvider,
+		Model:        cfg.Model,
+		Text:         result.Text,
+		FinishReason: result.FinishReason,
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = os.MkdirAll(cfg.CacheDir, 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(cfg.CacheDir, cacheKey(cfg, requestContents(request))+".json"), data, 0644)
+	}
+	if err != nil {
+		fmt.Print(i18n.T("ai.cache.write_failed", err))
+	}
+}
+
+// ClearCache remove todas as respostas em cache e retorna quantas foram removidas
+func ClearCache() (int, error) {
+	cfg := config.LoadConfig()
+	entries, err := os.ReadDir(cfg.CacheDir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao ler diretório de cache: %v", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cfg.CacheDir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("erro ao remover %s: %v", entry.Name(), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
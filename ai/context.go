@@ -0,0 +1,228 @@
+package ai
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContextTokens é o orçamento padrão de tokens para o conteúdo de um projeto existente
+const DefaultContextTokens = 30000
+
+// minTruncatedTokens é o menor trecho que vale a pena enviar de um arquivo truncado
+const minTruncatedTokens = 200
+
+// Prioridades dos arquivos no contexto, da mais alta para a mais baixa
+const (
+	rankManifest = iota
+	rankEntrypoint
+	rankConfig
+	rankDoc
+	rankSource
+	rankTest
+	rankLock
+)
+
+// manifestFileNames são arquivos que descrevem o projeto e suas dependências
+var manifestFileNames = map[string]bool{
+	"go.mod":           true,
+	"package.json":     true,
+	"Cargo.toml":       true,
+	"pyproject.toml":   true,
+	"requirements.txt": true,
+	"setup.py":         true,
+	"pom.xml":          true,
+	"build.gradle":     true,
+	"build.gradle.kts": true,
+	"composer.json":    true,
+	"Gemfile":          true,
+	"mix.exs":          true,
+	"deno.json":        true,
+}
+
+// lockFileNames são arquivos gerados que raramente ajudam o modelo
+var lockFileNames = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+// entrypointNames são nomes de arquivo (sem extensão) que costumam iniciar a aplicação
+var entrypointNames = map[string]bool{
+	"main":     true,
+	"index":    true,
+	"app":      true,
+	"server":   true,
+	"lib":      true,
+	"__main__": true,
+	"manage":   true,
+	"Program":  true,
+}
+
+// configExtensions são extensões de arquivos de configuração
+var configExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".toml": true,
+	".json": true,
+	".ini":  true,
+	".cfg":  true,
+	".conf": true,
+	".env":  true,
+}
+
+// ContextFile é um arquivo selecionado para o contexto
+type ContextFile struct {
+	Path      string
+	Content   string
+	Truncated bool
+}
+
+// ProjectContext resume um projeto existente para ser enviado ao modelo
+type ProjectContext struct {
+	// Tree lista todos os arquivos do projeto, em ordem alfabética
+	Tree []string
+	// Files contém os arquivos selecionados, em ordem de prioridade
+	Files []ContextFile
+	// Omitted lista os arquivos cujo conteúdo não coube no orçamento
+	Omitted []string
+	// Tokens é a estimativa de tokens do conteúdo selecionado
+	Tokens int
+}
+
+// estimateTokens aproxima a quantidade de tokens de um texto (cerca de 4 caracteres por token)
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// contextRank classifica um arquivo para decidir a ordem em que ele entra no contexto
+func contextRank(rel string) int {
+	base := path.Base(rel)
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	lower := strings.ToLower(base)
+
+	switch {
+	case lockFileNames[base]:
+		return rankLock
+	case manifestFileNames[base]:
+		return rankManifest
+	case strings.Contains(lower, "_test.") || strings.Contains(lower, ".test.") || strings.Contains(lower, ".spec.") ||
+		strings.HasPrefix(lower, "test_") || strings.HasPrefix(rel, "test/") || strings.HasPrefix(rel, "tests/"):
+		return rankTest
+	case entrypointNames[name] && ext != "" && !configExtensions[ext]:
+		return rankEntrypoint
+	case configExtensions[ext] || base == "Dockerfile" || base == "Makefile" || strings.HasPrefix(base, ".env"):
+		return rankConfig
+	case ext == ".md" || ext == ".txt" || ext == ".rst":
+		return rankDoc
+	default:
+		return rankSource
+	}
+}
+
+// CollectProjectContext lê o projeto em dir, ordena os arquivos por relevância (manifestos,
+// pontos de entrada e configurações primeiro) e inclui o conteúdo até o orçamento de tokens.
+// O primeiro arquivo que não cabe inteiro é truncado; os demais são apenas listados.
+// Os arquivos em focus, quando informados, entram antes de todos os outros. Arquivos .env
+// (exceto modelos como .env.example) costumam guardar credenciais reais e aparecem apenas
+// na árvore, nunca com o conteúdo.
+func CollectProjectContext(dir string, tokenBudget int, focus ...string) (*ProjectContext, error) {
+	if tokenBudget <= 0 {
+		tokenBudget = DefaultContextTokens
+	}
+
+	contents := make(map[string]string)
+	ctx := &ProjectContext{}
+	err := walkProjectFiles(dir, nil, func(rel string, data []byte) error {
+		ctx.Tree = append(ctx.Tree, rel)
+		if !isEnvFile(rel) {
+			contents[rel] = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler projeto: %v", err)
+	}
+	sort.Strings(ctx.Tree)
+
+	focused := make(map[string]bool)
+	for _, rel := range focus {
+		focused[rel] = true
+	}
+
+	ranked := make([]string, 0, len(contents))
+	for _, rel := range ctx.Tree {
+		if _, ok := contents[rel]; ok {
+			ranked = append(ranked, rel)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if focused[ranked[i]] != focused[ranked[j]] {
+			return focused[ranked[i]]
+		}
+		ri, rj := contextRank(ranked[i]), contextRank(ranked[j])
+		if ri != rj {
+			return ri < rj
+		}
+		// Arquivos mais próximos da raiz tendem a ser mais relevantes
+		return strings.Count(ranked[i], "/") < strings.Count(ranked[j], "/")
+	})
+
+	for _, rel := range ranked {
+		content := contents[rel]
+		tokens := estimateTokens(content)
+		remaining := tokenBudget - ctx.Tokens
+		switch {
+		case contextRank(rel) == rankLock && !focused[rel]:
+			ctx.Omitted = append(ctx.Omitted, rel)
+		case tokens <= remaining:
+			ctx.Files = append(ctx.Files, ContextFile{Path: rel, Content: content})
+			ctx.Tokens += tokens
+		case remaining >= minTruncatedTokens:
+			ctx.Files = append(ctx.Files, ContextFile{Path: rel, Content: truncateUTF8(content, remaining*4), Truncated: true})
+			ctx.Tokens = tokenBudget
+		default:
+			ctx.Omitted = append(ctx.Omitted, rel)
+		}
+	}
+
+	return ctx, nil
+}
+
+// truncateUTF8 corta o texto em até n bytes sem quebrar um caractere multibyte
+func truncateUTF8(text string, n int) string {
+	if n >= len(text) {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
+
+// Prompt formata a árvore e o conteúdo selecionado como seção de um prompt
+func (c *ProjectContext) Prompt() string {
+	var b strings.Builder
+	b.WriteString("Arquivos do projeto:\n")
+	for _, rel := range c.Tree {
+		fmt.Fprintf(&b, "- %s\n", rel)
+	}
+	for _, file := range c.Files {
+		fmt.Fprintf(&b, "\n=== %s ===\n%s\n", file.Path, file.Content)
+		if file.Truncated {
+			b.WriteString("[... conteúdo truncado ...]\n")
+		}
+	}
+	if len(c.Omitted) > 0 {
+		fmt.Fprintf(&b, "\n(Conteúdo omitido por limite de tamanho: %s)\n", strings.Join(c.Omitted, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"zion/i18n"
+	"zion/prompts"
+)
+
+// conversationFile é o arquivo, dentro do projeto, onde a conversa de refinamento é salva
+const conversationFile = ".zion/conversation.json"
+
+// Conversation mantém o histórico de turnos com o modelo para refinar um projeto.
+// O último turno do modelo é sempre o manifesto atual do projeto.
+type Conversation struct {
+	Contents []Content `json:"contents"`
+}
+
+// NewConversation inicia uma conversa a partir do prompt enviado e do manifesto obtido
+func NewConversation(prompt, manifest string) *Conversation {
+	return &Conversation{
+		Contents: []Content{
+			{Parts: []Part{{Text: prompt}}, Role: "user"},
+			{Parts: []Part{{Text: manifest}}, Role: "model"},
+		},
+	}
+}
+
+// ConversationFromProject retoma a conversa salva no projeto ou, se não houver,
+// inicia uma nova a partir dos arquivos presentes no diretório
+func ConversationFromProject(dir string) (*Conversation, error) {
+	data, err := os.ReadFile(filepath.Join(dir, conversationFile))
+	if err == nil {
+		var conv Conversation
+		if err := json.Unmarshal(data, &conv); err != nil {
+			return nil, fmt.Errorf("erro ao ler conversa salva: %v", err)
+		}
+		return &conv, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("erro ao ler conversa salva: %v", err)
+	}
+
+	manifest, err := ReadProjectManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := renderPrompt("refine_start.tmpl", prompts.Data{ProjectName: filepath.Base(filepath.Clean(dir))})
+	if err != nil {
+		return nil, err
+	}
+	return NewConversation(prompt, manifest), nil
+}
+
+// Manifest retorna o manifesto atual, isto é, a última resposta do modelo
+func (c *Conversation) Manifest() string {
+	for i := len(c.Contents) - 1; i >= 0; i-- {
+		if c.Contents[i].Role == "model" && len(c.Contents[i].Parts) > 0 {
+			return c.Contents[i].Parts[0].Text
+		}
+	}
+	return ""
+}
+
+// refinementPrompt monta, a partir de refine.tmpl, a mensagem de alteração enviada em cada turno
+func refinementPrompt(instruction string) (string, error) {
+	return renderPrompt("refine.tmpl", prompts.Data{Instruction: instruction})
+}
+
+// Refine envia uma instrução de alteração e retorna o novo manifesto.
+// A conversa só é alterada quando a alteração é aceita com Accept.
+func (c *Conversation) Refine(instruction string) (string, error) {
+	prompt, err := refinementPrompt(instruction)
+	if err != nil {
+		return "", err
+	}
+	contents := append(append([]Content(nil), c.Contents...),
+		Content{Parts: []Part{{Text: prompt}}, Role: "user"},
+	)
+
+	fmt.Print(i18n.T("ai.refine.sending"))
+	result, err := sendGeminiRequest(buildGeminiContentsRequest(contents))
+	if err != nil {
+		return "", err
+	}
+	if result, err = continueTruncatedResponse(contents, result, nil); err != nil {
+		return "", err
+	}
+
+	response, err := cleanResponseJSON(result.Text)
+	if err != nil {
+		return "", err
+	}
+	return processScaffoldResponse(response)
+}
+
+// Accept registra na conversa a alteração aceita e o manifesto resultante
+func (c *Conversation) Accept(instruction, manifest string) error {
+	prompt, err := refinementPrompt(instruction)
+	if err != nil {
+		return err
+	}
+	c.Contents = append(c.Contents,
+		Content{Parts: []Part{{Text: prompt}}, Role: "user"},
+		Content{Parts: []Part{{Text: manifest}}, Role: "model"},
+	)
+	return nil
+}
+
+// Save grava a conversa no diretório do projeto para que possa ser retomada com zion refine
+func (c *Conversation) Save(dir string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar conversa: %v", err)
+	}
+	path := filepath.Join(dir, conversationFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar conversa: %v", err)
+	}
+	return nil
+}
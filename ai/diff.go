@@ -0,0 +1,206 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines é o número de linhas de contexto em cada hunk do diff unificado
+const diffContextLines = 3
+
+// maxDiffCells limita o tamanho da tabela LCS; acima disso o trecho divergente
+// é tratado como um único bloco removido/adicionado
+const maxDiffCells = 4_000_000
+
+// diffOp é uma linha do diff: ' ' mantida, '-' removida ou '+' adicionada
+type diffOp struct {
+	Kind byte
+	Line string
+}
+
+// splitLines divide o texto em linhas, sem a quebra de linha final
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines calcula as operações que transformam a em b
+func diffLines(a, b []string) []diffOp {
+	// Prefixo e sufixo comuns ficam fora da tabela LCS
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle calcula o diff do trecho divergente usando a maior subsequência comum
+func diffMiddle(a, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > maxDiffCells || len(a) == 0 || len(b) == 0 {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] é o tamanho da maior subsequência comum de a[i:] e b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// DiffStat conta as linhas adicionadas e removidas entre duas versões de um arquivo
+func DiffStat(oldText, newText string) (added, removed int) {
+	if oldText == newText {
+		return 0, 0
+	}
+	for _, op := range diffLines(splitLines(oldText), splitLines(newText)) {
+		switch op.Kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// UnifiedDiff gera um diff unificado entre duas versões de um arquivo.
+// Retorna "" quando não há diferenças.
+func UnifiedDiff(path, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	oldName, newName := "a/"+path, "b/"+path
+	if oldText == "" {
+		oldName = "/dev/null"
+	}
+	if newText == "" {
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Agrupa as alterações em hunks com linhas de contexto ao redor
+	for start := 0; start < len(ops); {
+		if ops[start].Kind == ' ' {
+			start++
+			continue
+		}
+
+		hunkStart := start - diffContextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := start
+		for hunkEnd < len(ops) {
+			if ops[hunkEnd].Kind != ' ' {
+				hunkEnd++
+				continue
+			}
+			// Fecha o hunk quando há mais que 2x o contexto de linhas mantidas em sequência
+			run := hunkEnd
+			for run < len(ops) && ops[run].Kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-hunkEnd > 2*diffContextLines {
+				hunkEnd += diffContextLines
+				if hunkEnd > run {
+					hunkEnd = run
+				}
+				break
+			}
+			hunkEnd = run
+		}
+
+		oldLine, newLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.Kind != '+' {
+				oldLine++
+			}
+			if op.Kind != '-' {
+				newLine++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.Kind != '+' {
+				oldCount++
+			}
+			if op.Kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			fmt.Fprintf(&b, "%c%s\n", op.Kind, op.Line)
+		}
+		start = hunkEnd
+	}
+
+	return b.String()
+}
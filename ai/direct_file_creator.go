@@ -1,38 +1,47 @@
 package ai
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"zion/config"
+	"zion/i18n"
 )
 
 // ExtractAndCreateProject extrai diretamente os diretórios e arquivos do JSON
 // e cria a estrutura do projeto sem tentar interpretar o conteúdo
 func ExtractAndCreateProject(projectName string, jsonStr string) error {
-	// Remover blocos de código markdown se presentes
-	if strings.HasPrefix(jsonStr, "```json\n") && strings.HasSuffix(jsonStr, "\n```") {
-		jsonStr = strings.TrimPrefix(jsonStr, "```json\n")
-		jsonStr = strings.TrimSuffix(jsonStr, "\n```")
+	manifest, err := ManifestFromResponse(filepath.Base(projectName), jsonStr)
+	if err != nil {
+		return err
 	}
+	return CreateProjectFromManifest(projectName, manifest)
+}
 
-	var scaffoldResp ScaffoldResponse
-	err := json.Unmarshal([]byte(jsonStr), &scaffoldResp)
-	if err != nil {
-		return fmt.Errorf("erro ao fazer parse do JSON: %v", err)
+// CreateProjectFromManifest cria no diretório do projeto os diretórios e arquivos do manifesto.
+// Os arquivos copy-from-template e url são obtidos antes; os que falharem são apontados e
+// ficam de fora do projeto e do manifesto. Em seguida, o manifesto passa pela verificação
+// de segredos (ScanSecrets), que pode alterá-lo ou interromper a criação.
+func CreateProjectFromManifest(projectName string, manifest *ProjectManifest) error {
+	for _, problem := range manifest.Resolve(TemplateDirs()) {
+		fmt.Printf("⚠️  %v\n", problem)
+	}
+	if err := checkSecrets(manifest); err != nil {
+		return err
 	}
 
 	// Criar o diretório raiz do projeto
 	if err := os.MkdirAll(projectName, 0755); err != nil {
 		return fmt.Errorf("erro ao criar diretório raiz '%s': %v", projectName, err)
 	}
-	fmt.Printf("\n📁 Criando diretório raiz: %s\n", projectName)
+	fmt.Print(i18n.T("ai.create.root", projectName))
 
 	// Criar diretórios
-	if len(scaffoldResp.Structure.Directories) > 0 {
-		fmt.Println("\n📂 Criando diretórios:")
-		for _, dir := range scaffoldResp.Structure.Directories {
+	if len(manifest.Directories) > 0 {
+		fmt.Print(i18n.T("ai.create.dirs"))
+		for _, dir := range manifest.Directories {
 			dirPath := filepath.Join(projectName, dir)
 			fmt.Printf("   ├── %s\n", dir)
 			if err := os.MkdirAll(dirPath, 0755); err != nil {
@@ -42,42 +51,80 @@ func ExtractAndCreateProject(projectName string, jsonStr string) error {
 	}
 
 	// Criar arquivos
-	if len(scaffoldResp.Structure.Files) > 0 {
-		fmt.Println("\n📄 Criando arquivos:")
-		for filePath, content := range scaffoldResp.Structure.Files {
-			var contentStr string
-			if strContent, ok := content.(string); ok {
-				contentStr = strContent
-				contentStr = ProcessEscapedChars(contentStr)
-			} else {
-				contentBytes, err := json.MarshalIndent(content, "", "  ")
-				if err != nil {
-					return fmt.Errorf("erro ao serializar conteúdo JSON para '%s': %v", filePath, err)
-				}
-				contentStr = string(contentBytes)
+	if len(manifest.Files) > 0 {
+		fmt.Print(i18n.T("ai.create.files"))
+		for _, file := range manifest.Files {
+			fmt.Printf("   ├── %s\n", file.Path)
+			if err := writeManifestFile(projectName, file); err != nil {
+				return err
 			}
+		}
+	}
 
-			fullPath := filepath.Join(projectName, filePath)
-			fmt.Printf("   ├── %s\n", filePath)
+	// Exibir resumo
+	fmt.Print(i18n.T("ai.create.summary"))
+	fmt.Print(i18n.T("ai.create.summary_dirs", len(manifest.Directories)))
+	fmt.Print(i18n.T("ai.create.summary_files", len(manifest.Files)))
 
-			// Garantir que o diretório pai exista
-			parentDir := filepath.Dir(fullPath)
-			if err := os.MkdirAll(parentDir, 0755); err != nil {
-				return fmt.Errorf("erro ao criar diretório pai para '%s': %v", filePath, err)
-			}
+	return nil
+}
 
-			if err := os.WriteFile(fullPath, []byte(contentStr), 0644); err != nil {
-				return fmt.Errorf("erro ao criar arquivo '%s': %v", filePath, err)
-			}
+// fileContentString converte o conteúdo de um arquivo do manifesto em texto: strings já
+// foram decodificadas pelo parse do JSON, e objetos JSON são formatados com
+// formatJSONContent. O package.json passa pelo modelo tipado, que ordena as dependências.
+// O fallback para respostas escapadas duas vezes depende da resposta inteira e fica em
+// decodeDoubleEscaped.
+func fileContentString(filePath string, content interface{}) (string, error) {
+	if text, ok := content.(string); ok {
+		if isPackageJSON(filePath) {
+			text = normalizePackageJSON(text)
 		}
+		return text, nil
 	}
+	return formatJSONContent(filePath, content)
+}
 
-	// Exibir resumo
-	fmt.Printf("\n📊 Resumo da estrutura criada:\n")
-	fmt.Printf("   ├── %d diretórios\n", len(scaffoldResp.Structure.Directories))
-	fmt.Printf("   └── %d arquivos\n", len(scaffoldResp.Structure.Files))
+// formatJSONContent grava um objeto JSON recebido do modelo com a indentação configurada
+// para o tipo de arquivo (json_style em config.yaml). Conteúdo recebido como json.RawMessage
+// mantém a ordem das chaves; um map, cuja ordem já se perdeu, sai com as chaves ordenadas
+// e, no package.json, com os campos na ordem convencional.
+func formatJSONContent(filePath string, content interface{}) (string, error) {
+	raw, ordered := content.(json.RawMessage)
+	if !ordered {
+		var err error
+		if raw, err = marshalJSONValue(content); err != nil {
+			return "", err
+		}
+	}
+	style := config.LoadConfig().JSONStyleFor(filePath)
+	indent := style.IndentString()
 
-	return nil
+	var out []byte
+	if isPackageJSON(filePath) {
+		pkg, err := ParsePackageJSON(raw)
+		if err != nil {
+			return "", err
+		}
+		pkg.SortDependencies()
+		if !ordered {
+			pkg.CanonicalOrder()
+		}
+		if out, err = pkg.Marshal(indent); err != nil {
+			return "", err
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, raw, "", indent); err != nil {
+			return "", err
+		}
+		out = buf.Bytes()
+	}
+
+	out = bytes.TrimRight(out, "\n")
+	if style.EndsWithNewline() {
+		out = append(out, '\n')
+	}
+	return string(out), nil
 }
 
 // As funções auxiliares foram movidas para o arquivo file_utils.go
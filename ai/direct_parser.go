@@ -143,9 +143,7 @@ func ExtractDirectoriesAndFiles(response string) ([]string, map[string]string) {
 						}
 						
 						// Desescapar o conteúdo
-						currentContent = strings.ReplaceAll(currentContent, "\\\"", "\"")
-						currentContent = strings.ReplaceAll(currentContent, "\\n", "\n")
-						currentContent = strings.ReplaceAll(currentContent, "\\t", "\t")
+						currentContent = ProcessEscapedChars(currentContent)
 						
 						// Adicionar o arquivo ao mapa
 						files[currentFile] = currentContent
@@ -168,9 +166,7 @@ func ExtractDirectoriesAndFiles(response string) ([]string, map[string]string) {
 					}
 					
 					// Desescapar o conteúdo
-					currentContent = strings.ReplaceAll(currentContent, "\\\"", "\"")
-					currentContent = strings.ReplaceAll(currentContent, "\\n", "\n")
-					currentContent = strings.ReplaceAll(currentContent, "\\t", "\t")
+					currentContent = ProcessEscapedChars(currentContent)
 					
 					// Adicionar o arquivo ao mapa
 					files[currentFile] = currentContent
@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// editorConfigSection é uma seção de um .editorconfig: o padrão de arquivos e as propriedades
+type editorConfigSection struct {
+	pattern    *regexp.Regexp
+	properties map[string]string
+}
+
+// EditorConfig guarda as seções do .editorconfig da raiz de um projeto
+type EditorConfig struct {
+	sections []editorConfigSection
+}
+
+// LoadEditorConfig lê o .editorconfig da raiz do projeto. Sem o arquivo, retorna uma
+// configuração vazia, que não altera nenhum arquivo.
+func LoadEditorConfig(dir string) (*EditorConfig, error) {
+	ec := &EditorConfig{}
+	file, err := os.Open(filepath.Join(dir, ".editorconfig"))
+	if os.IsNotExist(err) {
+		return ec, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var current *editorConfigSection
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			pattern, err := editorConfigGlob(line[1 : len(line)-1])
+			if err != nil {
+				// Seções com padrões que não entendemos são ignoradas
+				current = nil
+				continue
+			}
+			ec.sections = append(ec.sections, editorConfigSection{pattern: pattern, properties: make(map[string]string)})
+			current = &ec.sections[len(ec.sections)-1]
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || current == nil {
+			continue
+		}
+		current.properties[strings.ToLower(strings.TrimSpace(key))] = strings.ToLower(strings.TrimSpace(value))
+	}
+	return ec, scanner.Err()
+}
+
+// Properties retorna as propriedades que se aplicam a um arquivo; seções posteriores
+// sobrescrevem as anteriores
+func (ec *EditorConfig) Properties(rel string) map[string]string {
+	properties := make(map[string]string)
+	rel = filepath.ToSlash(rel)
+	for _, section := range ec.sections {
+		if !section.pattern.MatchString(rel) {
+			continue
+		}
+		for key, value := range section.properties {
+			properties[key] = value
+		}
+	}
+	return properties
+}
+
+// editorConfigGlob converte o padrão de uma seção em expressão regular. Padrões sem
+// barra valem em qualquer diretório; com barra, a partir da raiz do projeto.
+func editorConfigGlob(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	braces := 0
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			b.WriteString(".*")
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end
+		case c == '{':
+			end := strings.IndexByte(glob[i:], '}')
+			if end > 0 {
+				// {1..3}: intervalo numérico
+				if lo, hi, ok := strings.Cut(glob[i+1:i+end], ".."); ok {
+					if from, err := strconv.Atoi(lo); err == nil {
+						if to, err := strconv.Atoi(hi); err == nil && to >= from && to-from <= 1000 {
+							var options []string
+							for n := from; n <= to; n++ {
+								options = append(options, strconv.Itoa(n))
+							}
+							b.WriteString("(?:" + strings.Join(options, "|") + ")")
+							i += end
+							continue
+						}
+					}
+				}
+			}
+			braces++
+			b.WriteString("(?:")
+		case c == '}' && braces > 0:
+			braces--
+			b.WriteString(")")
+		case c == ',' && braces > 0:
+			b.WriteString("|")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	for ; braces > 0; braces-- {
+		b.WriteString(")")
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// normalizeWhitespace aplica a um texto as propriedades trim_trailing_whitespace,
+// insert_final_newline e end_of_line do .editorconfig
+func normalizeWhitespace(content string, properties map[string]string) string {
+	eol, hasEOL := map[string]string{"lf": "\n", "crlf": "\r\n", "cr": "\r"}[properties["end_of_line"]]
+	trim := properties["trim_trailing_whitespace"] == "true"
+	if !hasEOL && !trim && properties["insert_final_newline"] == "" {
+		return content
+	}
+
+	if !hasEOL {
+		eol = "\n"
+		if strings.Contains(content, "\r\n") {
+			eol = "\r\n"
+		}
+	}
+	text := strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\r", "\n")
+	lines := strings.Split(text, "\n")
+	if trim {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+	text = strings.Join(lines, "\n")
+
+	switch properties["insert_final_newline"] {
+	case "true":
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+	case "false":
+		text = strings.TrimRight(text, "\n")
+	}
+	return strings.ReplaceAll(text, "\n", eol)
+}
@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"zion/i18n"
+	"zion/prompts"
+)
+
+// GenerateFeature pede ao modelo os arquivos novos e modificados para implementar
+// uma funcionalidade no projeto em dir e retorna o manifesto processado. tokenBudget
+// limita o conteúdo do projeto enviado como contexto.
+func GenerateFeature(dir, feature string, tokenBudget int) (string, error) {
+	ctx, err := CollectProjectContext(dir, tokenBudget)
+	if err != nil {
+		return "", err
+	}
+	fmt.Print(i18n.T("ai.feature.context", len(ctx.Tree), len(ctx.Files), ctx.Tokens))
+
+	prompt, err := renderPrompt("feature.tmpl", prompts.Data{
+		ProjectContext: ctx.Prompt(),
+		Feature:        feature,
+		DocLanguage:    i18n.LanguageName(DocLanguage()),
+	})
+	if err != nil {
+		return "", err
+	}
+	result, err := requestGemini(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := cleanResponseJSON(result.Text)
+	if err != nil {
+		return "", err
+	}
+	return processScaffoldResponse(response)
+}
+
+// FeatureChanges compara os arquivos de um manifesto parcial com os arquivos em disco,
+// mantendo o modo (como 0755 de um script) informado em cada arquivo
+func FeatureChanges(dir, manifest string) ([]FileChange, error) {
+	m, err := resolvedManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return ManifestChanges(dir, m)
+}
+
+// diskChanges compara o conteúdo esperado de cada arquivo com o arquivo em disco
+func diskChanges(dir string, files map[string]string) ([]FileChange, error) {
+	var changes []FileChange
+	for path, newContent := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		switch {
+		case os.IsNotExist(err):
+			changes = append(changes, FileChange{Path: path, Kind: ChangeAdded, NewContent: newContent})
+		case err != nil:
+			return nil, fmt.Errorf("erro ao ler %s: %v", path, err)
+		case string(data) != newContent:
+			changes = append(changes, FileChange{Path: path, Kind: ChangeModified, OldContent: string(data), NewContent: newContent})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
@@ -1,10 +1,16 @@
 package ai
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+	"zion/i18n"
 )
 
 // FindUnescapedQuote encontra a próxima aspas não escapada
@@ -38,23 +44,120 @@ func FindMatchingBrace(str string, startPos int) int {
 	return -1
 }
 
-// ProcessEscapedChars processa caracteres escapados no conteúdo
+// ProcessEscapedChars decodifica, em uma única passagem, os escapes de uma string JSON
+// extraída da resposta sem passar por json.Unmarshal (\", \\, \n, \t, \uXXXX...).
+// Cada escape é decodificado uma vez: \\n vira uma barra seguida de n, e não uma quebra
+// de linha. Escapes que não existem em JSON, como \@, perdem apenas a barra.
 func ProcessEscapedChars(content string) string {
-	// Substituir caracteres escapados
-	replacements := map[string]string{
-		"\\\"": "\"",
-		"\\n":  "\n",
-		"\\t":  "\t",
-		"\\r":  "\r",
-		"\\\\": "\\",
+	if !strings.Contains(content, "\\") {
+		return content
 	}
-	
-	result := content
-	for escaped, unescaped := range replacements {
-		result = strings.ReplaceAll(result, escaped, unescaped)
+	var b strings.Builder
+	b.Grow(len(content))
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c != '\\' || i+1 == len(content) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch content[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, size := decodeUnicodeEscape(content[i-1:])
+			if size == 0 {
+				b.WriteString(`\u`)
+				continue
+			}
+			b.WriteRune(r)
+			i += size - 2
+		default:
+			// \", \\, \/ e escapes inválidos, como \@
+			b.WriteByte(content[i])
+		}
 	}
-	
-	return result
+	return b.String()
+}
+
+// decodeUnicodeEscape decodifica um escape \uXXXX no início de s, juntando os pares
+// substitutos do UTF-16. Retorna o tamanho consumido, ou 0 se o escape for inválido.
+func decodeUnicodeEscape(s string) (rune, int) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, 0
+	}
+	r, ok := parseHexRune(s[2:6])
+	if !ok {
+		return 0, 0
+	}
+	if utf16.IsSurrogate(r) {
+		if len(s) >= 12 && s[6] == '\\' && s[7] == 'u' {
+			if low, ok := parseHexRune(s[8:12]); ok {
+				if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+					return pair, 12
+				}
+			}
+		}
+		return utf8.RuneError, 6
+	}
+	return r, 6
+}
+
+// parseHexRune lê os quatro dígitos hexadecimais de um escape \uXXXX
+func parseHexRune(s string) (rune, bool) {
+	code, err := strconv.ParseUint(s, 16, 16)
+	return rune(code), err == nil
+}
+
+// doubleEscaped indica se um texto já decodificado parece ter sido escapado duas vezes
+// pelo modelo: tudo em uma linha, com mais de uma sequência \n literal, e ainda uma
+// string JSON válida (aspas internas também escapadas). Um arquivo de uma linha como
+// printf("a\nb\n") também passa; por isso a decisão é tomada para a resposta inteira, em
+// decodeDoubleEscaped.
+func doubleEscaped(text string) bool {
+	if strings.Contains(text, "\n") || strings.Count(text, `\n`) < 2 {
+		return false
+	}
+	var decoded string
+	return json.Unmarshal([]byte(`"`+text+`"`), &decoded) == nil
+}
+
+// decodeDoubleEscaped aplica o fallback para respostas escapadas duas vezes aos arquivos de
+// texto de uma resposta, por caminho. A resposta só é considerada escapada duas vezes quando
+// nenhum arquivo tem quebras de linha reais e ao menos um é apontado por doubleEscaped; se
+// algum arquivo chegou com várias linhas, nenhum é alterado. Os arquivos decodificados são
+// relatados e retornados em ordem.
+func decodeDoubleEscaped(files map[string]string) []string {
+	var candidates []string
+	for filePath, text := range files {
+		if strings.Contains(text, "\n") {
+			return nil
+		}
+		if doubleEscaped(text) {
+			candidates = append(candidates, filePath)
+		}
+	}
+	sort.Strings(candidates)
+
+	var decoded []string
+	for _, filePath := range candidates {
+		var text string
+		if err := json.Unmarshal([]byte(`"`+files[filePath]+`"`), &text); err != nil {
+			continue
+		}
+		fmt.Print(i18n.T("ai.double_escaped", filePath))
+		files[filePath] = text
+		decoded = append(decoded, filePath)
+	}
+	return decoded
 }
 
 // CreateFile cria um arquivo com o conteúdo especificado
@@ -67,7 +170,7 @@ func CreateFile(projectName, filePath, content string) error {
 		return fmt.Errorf("erro ao criar diretório pai para %s: %v", filePath, err)
 	}
 	
-	fmt.Println("Criando arquivo:", filePath)
+	fmt.Print(i18n.T("ai.creating_file", filePath))
 	
 	// Escrever o conteúdo no arquivo
 	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
@@ -76,48 +179,3 @@ func CreateFile(projectName, filePath, content string) error {
 	
 	return nil
 }
-
-// isJSONFile verifica se o arquivo é um arquivo JSON ou similar que não deve ter escapes adicionados
-func isJSONFile(filePath string) bool {
-	jsonFiles := []string{
-		"package.json",
-		"tsconfig.json",
-		"angular.json",
-		"next.config.js",
-		"webpack.config.js",
-		".eslintrc.json",
-		"composer.json",
-		"manifest.json",
-		"app.json",
-		"project.json",
-	}
-	
-	fileName := filepath.Base(filePath)
-	
-	for _, jsonFile := range jsonFiles {
-		if fileName == jsonFile {
-			return true
-		}
-	}
-	
-	// Verificar pela extensão
-	ext := filepath.Ext(filePath)
-	return ext == ".json" || ext == ".jsonc"
-}
-
-// PreserveJSONFormat preserva o formato original de arquivos JSON, removendo apenas escapes desnecessários
-func PreserveJSONFormat(content string) string {
-	// Remover escapes desnecessários que podem afetar o formato JSON
-	unescaped := content
-	
-	// Remover escapes de caracteres especiais que não devem ser escapados em JSON
-	unescaped = strings.ReplaceAll(unescaped, "\\@", "@")
-	unescaped = strings.ReplaceAll(unescaped, "\\\"", "\"")
-	
-	// Preservar quebras de linha reais, mas remover escapes de quebra de linha
-	unescaped = strings.ReplaceAll(unescaped, "\\n", "\n")
-	unescaped = strings.ReplaceAll(unescaped, "\\r", "\r")
-	unescaped = strings.ReplaceAll(unescaped, "\\t", "\t")
-	
-	return unescaped
-}
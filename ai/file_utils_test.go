@@ -0,0 +1,156 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessEscapedChars(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"sem escapes", "texto simples", "texto simples"},
+		{"quebras de linha e tabs", `a\nb\tc\r\n`, "a\nb\tc\r\n"},
+		{"aspas e barra", `diz \"oi\" em C:\\dir\/x`, `diz "oi" em C:\dir/x`},
+		{"barra escapada antes de n", `printf("a\\n")`, `printf("a\n")`},
+		{"duas barras escapadas", `\\\\servidor`, `\\servidor`},
+		{"barra escapada e quebra real", `\\\n`, "\\\n"},
+		{"backspace e form feed", `\b\f`, "\b\f"},
+		{"unicode", `ol\u00e1 \u4e16`, "olá 世"},
+		{"par substituto", `\ud83d\ude80`, "🚀"},
+		{"substituto sozinho", `\ud83dx`, "\uFFFDx"},
+		{"unicode inválido", `\u12G4`, `\u12G4`},
+		{"unicode curto", `\u12`, `\u12`},
+		{"escape inválido perde a barra", `user\@example.com`, "user@example.com"},
+		{"barra no fim", `fim\`, `fim\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProcessEscapedChars(tt.input); got != tt.want {
+				t.Errorf("ProcessEscapedChars(%q) = %q, esperado %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoubleEscaped(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"arquivo achatado", `package main\n\nfunc main() {\n\tprintln(\"oi\")\n}\n`, true},
+		{"uma sequência só", `linha\n`, false},
+		{"quebra de linha real", "a\\nb\\n\nc", false},
+		{"aspas sem escape", `fmt.Printf("a\nb\n")`, false},
+		{"printf de uma linha", `printf(\"a\\nb\\n\");`, true},
+		{"sem sequências", "texto", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doubleEscaped(tt.input); got != tt.want {
+				t.Errorf("doubleEscaped(%q) = %v, esperado %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDoubleEscaped(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    map[string]string
+		decoded []string
+	}{
+		{
+			name: "resposta achatada",
+			files: map[string]string{
+				"main.go":    `package main\n\nfunc main() {\n\tprintln(\"oi\")\n}\n`,
+				"README.md":  `# Projeto\n\nDescrição\n`,
+				".gitignore": "bin",
+			},
+			want: map[string]string{
+				"main.go":    "package main\n\nfunc main() {\n\tprintln(\"oi\")\n}\n",
+				"README.md":  "# Projeto\n\nDescrição\n",
+				".gitignore": "bin",
+			},
+			decoded: []string{"README.md", "main.go"},
+		},
+		{
+			name: "arquivo de uma linha em resposta normal",
+			files: map[string]string{
+				"a.c":    `printf("a\nb\n");`,
+				"b.c":    "int main() {\n  return 0;\n}\n",
+				"re.txt": `^\d+\n\w+\n$`,
+			},
+			want: map[string]string{
+				"a.c":    `printf("a\nb\n");`,
+				"b.c":    "int main() {\n  return 0;\n}\n",
+				"re.txt": `^\d+\n\w+\n$`,
+			},
+		},
+		{
+			name: "JSON minificado em resposta normal",
+			files: map[string]string{
+				"data.json": `{"msg":"a\nb\nc"}`,
+				"main.js":   "console.log(1);\n",
+			},
+			want: map[string]string{
+				"data.json": `{"msg":"a\nb\nc"}`,
+				"main.js":   "console.log(1);\n",
+			},
+		},
+		{
+			name:  "sem candidatos",
+			files: map[string]string{"a.txt": "a", "b.txt": `b\n`},
+			want:  map[string]string{"a.txt": "a", "b.txt": `b\n`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded := decodeDoubleEscaped(tt.files)
+			if !reflect.DeepEqual(tt.files, tt.want) {
+				t.Errorf("arquivos = %q, esperado %q", tt.files, tt.want)
+			}
+			if !reflect.DeepEqual(decoded, tt.decoded) {
+				t.Errorf("decodificados = %q, esperado %q", decoded, tt.decoded)
+			}
+		})
+	}
+}
+
+func TestManifestFromResponseDoubleEscaped(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     map[string]string
+	}{
+		{
+			name:     "resposta achatada",
+			response: `{"structure":{"directories":[],"files":{"main.go":"package main\\n\\nfunc main() {}\\n","pkg.json":{"name":"x"}}}}`,
+			want:     map[string]string{"main.go": "package main\n\nfunc main() {}\n", "pkg.json": "{\n  \"name\": \"x\"\n}\n"},
+		},
+		{
+			name:     "printf de uma linha ao lado de arquivos normais",
+			response: `{"structure":{"directories":[],"files":{"a.c":"printf(\"a\\nb\\n\");","b.c":"int x;\nint y;\n"}}}`,
+			want:     map[string]string{"a.c": `printf("a\nb\n");`, "b.c": "int x;\nint y;\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			m, err := ManifestFromResponse("p", tt.response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.FileMap(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("arquivos = %q, esperado %q", got, tt.want)
+			}
+			for _, file := range m.Files {
+				if file.SHA256 != contentChecksum(file.Content) {
+					t.Errorf("%s: sha256 não corresponde ao conteúdo decodificado", file.Path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package ai
+
+import (
+	"fmt"
+	"zion/i18n"
+	"zion/prompts"
+)
+
+// GenerateFixes pede ao modelo as correções para os problemas encontrados no projeto em dir
+// e retorna o manifesto parcial processado. Os arquivos em focus, normalmente os que têm
+// problemas, entram primeiro no contexto limitado por tokenBudget.
+func GenerateFixes(dir, problems string, focus []string, tokenBudget int) (string, error) {
+	ctx, err := CollectProjectContext(dir, tokenBudget, focus...)
+	if err != nil {
+		return "", err
+	}
+	fmt.Print(i18n.T("ai.feature.context", len(ctx.Tree), len(ctx.Files), ctx.Tokens))
+
+	prompt, err := renderPrompt("fix.tmpl", prompts.Data{ProjectContext: ctx.Prompt(), Problems: problems})
+	if err != nil {
+		return "", err
+	}
+	result, err := requestGemini(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := cleanResponseJSON(result.Text)
+	if err != nil {
+		return "", err
+	}
+	return processScaffoldResponse(response)
+}
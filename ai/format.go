@@ -0,0 +1,241 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"zion/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formatadores executados dentro do Zion
+const (
+	FormatterGo           = "gofmt"
+	FormatterJSON         = "json"
+	FormatterYAML         = "yaml"
+	FormatterEditorConfig = "editorconfig"
+)
+
+// ExternalFormatter é um formatador externo, executado sobre os arquivos com as extensões
+// que ele trata, apenas quando listado em formatters no config.yaml e instalado
+type ExternalFormatter struct {
+	Name       string
+	Extensions []string
+	// Args vêm antes da lista de arquivos
+	Args []string
+}
+
+// ExternalFormatters são os formatadores externos conhecidos. JSON e YAML ficam de fora
+// do prettier, pois já são formatados pelo Zion com o estilo de json_style.
+var ExternalFormatters = []ExternalFormatter{
+	{Name: "prettier", Extensions: []string{".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".vue", ".css", ".scss", ".less", ".html", ".md"}, Args: []string{"--write"}},
+	{Name: "black", Extensions: []string{".py", ".pyi"}, Args: []string{"--quiet"}},
+	{Name: "rustfmt", Extensions: []string{".rs"}, Args: []string{"--edition", "2021"}},
+}
+
+// ConfiguredFormatters retorna os formatadores externos listados em formatters no config.yaml
+func ConfiguredFormatters() ([]ExternalFormatter, error) {
+	var formatters []ExternalFormatter
+	for _, name := range config.LoadConfig().Formatters {
+		found := false
+		for _, f := range ExternalFormatters {
+			if strings.EqualFold(f.Name, name) {
+				formatters = append(formatters, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var known []string
+			for _, f := range ExternalFormatters {
+				known = append(known, f.Name)
+			}
+			return nil, fmt.Errorf("formatador desconhecido em config.yaml: %s (%s)", name, strings.Join(known, ", "))
+		}
+	}
+	return formatters, nil
+}
+
+// Handles indica se o formatador trata o arquivo
+func (f ExternalFormatter) Handles(rel string) bool {
+	ext := strings.ToLower(path.Ext(rel))
+	for _, e := range f.Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// FormattedFile é um arquivo alterado por um ou mais formatadores, ou que um deles recusou
+type FormattedFile struct {
+	Path       string
+	Formatters []string
+	// Problem é o erro do formatador, como um arquivo Go que não compila; o arquivo
+	// fica como estava
+	Problem string
+}
+
+// FormatReport é o resultado da formatação de um projeto
+type FormatReport struct {
+	Files []FormattedFile
+}
+
+// Changed retorna os arquivos alterados
+func (r *FormatReport) Changed() []FormattedFile {
+	var changed []FormattedFile
+	for _, file := range r.Files {
+		if len(file.Formatters) > 0 {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+// Problems retorna os arquivos que os formatadores recusaram
+func (r *FormatReport) Problems() []FormattedFile {
+	var problems []FormattedFile
+	for _, file := range r.Files {
+		if file.Problem != "" {
+			problems = append(problems, file)
+		}
+	}
+	return problems
+}
+
+// AddChange registra que um formatador alterou o arquivo
+func (r *FormatReport) AddChange(rel, formatter string) {
+	for i := range r.Files {
+		if r.Files[i].Path == rel {
+			r.Files[i].Formatters = append(r.Files[i].Formatters, formatter)
+			return
+		}
+	}
+	r.Files = append(r.Files, FormattedFile{Path: rel, Formatters: []string{formatter}})
+}
+
+// AddProblem registra um erro de formatador
+func (r *FormatReport) AddProblem(rel, formatter string, err error) {
+	r.Files = append(r.Files, FormattedFile{Path: rel, Problem: fmt.Sprintf("%s: %v", formatter, err)})
+}
+
+// Sort ordena os arquivos pelo caminho
+func (r *FormatReport) Sort() {
+	sort.SliceStable(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+}
+
+// FormatProject formata os arquivos de texto do projeto em dir sem ferramentas externas:
+// Go com go/format, JSON com a indentação de json_style, YAML com indentação uniforme e,
+// em todos, os espaços no fim das linhas, a quebra de linha final e o fim de linha
+// definidos no .editorconfig do projeto. Com dryRun, nada é gravado.
+func FormatProject(dir string, dryRun bool) (*FormatReport, error) {
+	editorConfig, err := LoadEditorConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler .editorconfig: %v", err)
+	}
+	cfg := config.LoadConfig()
+
+	report := &FormatReport{}
+	err = walkProjectFiles(dir, nil, func(rel string, data []byte) error {
+		content := string(data)
+		properties := editorConfig.Properties(rel)
+
+		formatted, formatter, ferr := formatSource(rel, content, cfg, properties)
+		if ferr != nil {
+			report.AddProblem(rel, formatter, ferr)
+		} else if formatted != content {
+			report.AddChange(rel, formatter)
+			content = formatted
+		}
+		if normalized := normalizeWhitespace(content, properties); normalized != content {
+			report.AddChange(rel, FormatterEditorConfig)
+			content = normalized
+		}
+
+		if dryRun || content == string(data) {
+			return nil
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(content), 0644); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %v", rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Sort()
+	return report, nil
+}
+
+// formatSource formata um arquivo pelo tipo, retornando o formatador usado. Arquivos de
+// outros tipos são retornados como estão.
+func formatSource(rel, content string, cfg *config.Config, properties map[string]string) (string, string, error) {
+	switch strings.ToLower(path.Ext(rel)) {
+	case ".go":
+		out, err := format.Source([]byte(content))
+		if err != nil {
+			return content, FormatterGo, err
+		}
+		return string(out), FormatterGo, nil
+	case ".json":
+		// JSON com comentários (tsconfig.json, por exemplo) não é válido e fica como está
+		if !json.Valid([]byte(content)) {
+			return content, FormatterJSON, nil
+		}
+		style := cfg.JSONStyleFor(rel)
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, bytes.TrimSpace([]byte(content)), "", style.IndentString()); err != nil {
+			return content, FormatterJSON, err
+		}
+		if style.EndsWithNewline() {
+			buf.WriteByte('\n')
+		}
+		return buf.String(), FormatterJSON, nil
+	case ".yaml", ".yml":
+		indent := 2
+		if size, err := strconv.Atoi(properties["indent_size"]); err == nil && size > 0 {
+			indent = size
+		}
+		out, err := formatYAML(content, indent)
+		return out, FormatterYAML, err
+	}
+	return content, "", nil
+}
+
+// formatYAML reescreve os documentos de um arquivo YAML com indentação uniforme, mantendo
+// a ordem das chaves, os comentários e o estilo dos valores
+func formatYAML(content string, indent int) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	documents := 0
+	for {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err == io.EOF {
+			break
+		} else if err != nil {
+			return content, err
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return content, err
+		}
+		documents++
+	}
+	if err := encoder.Close(); err != nil {
+		return content, err
+	}
+	if documents == 0 {
+		return content, nil
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,178 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"zion/config"
+)
+
+// StreamProgress descreve o andamento de uma geração em streaming
+type StreamProgress struct {
+	Files  int
+	Tokens int
+}
+
+// callGeminiAPIStream chama streamGenerateContent e repassa cada trecho de texto
+// recebido para onChunk, junto com o total de tokens informado até o momento.
+// Retorna o texto completo concatenado.
+func callGeminiAPIStream(prompt string, onChunk func(text string, tokens int)) (*geminiResult, error) {
+	cfg := config.LoadConfig()
+	request := buildGeminiRequest(prompt)
+
+	// Uma resposta em cache é repassada de uma só vez, como se fosse um único trecho
+	if cached, ok := loadCachedResult(cfg, request); ok {
+		if onChunk != nil {
+			onChunk(cached.Text, 0)
+		}
+		return cached, nil
+	}
+
+	if cfg.GeminiAPIKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY não configurada")
+	}
+
+	url := geminiEndpoint(cfg, "streamGenerateContent") + "&alt=sse"
+
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar request: %v", err)
+	}
+
+	// O timeout precisa cobrir a leitura do stream inteiro
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API retornou status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var full strings.Builder
+	var finishReason string
+	var usage UsageMetadata
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+
+		// Cada evento do stream tem o mesmo formato de uma resposta generateContent
+		var chunk GeminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("erro ao processar evento do stream: %v\nEvento: %s", err, data)
+		}
+
+		var text strings.Builder
+		if len(chunk.Candidates) > 0 {
+			for _, part := range chunk.Candidates[0].Content.Parts {
+				text.WriteString(part.Text)
+			}
+			if chunk.Candidates[0].FinishReason != "" {
+				finishReason = chunk.Candidates[0].FinishReason
+			}
+		}
+		// O uso informado em cada evento é acumulado; vale o último
+		if chunk.UsageMetadata.TotalTokenCount > 0 {
+			usage = chunk.UsageMetadata
+		}
+		full.WriteString(text.String())
+		if onChunk != nil {
+			onChunk(text.String(), chunk.UsageMetadata.TotalTokenCount)
+		}
+	}
+	recordUsage(cfg, usage)
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler stream: %v", err)
+	}
+
+	if full.Len() == 0 {
+		return nil, fmt.Errorf("nenhuma resposta gerada da API")
+	}
+
+	result := &geminiResult{Text: full.String(), FinishReason: finishReason, Usage: usage}
+	storeCachedResult(cfg, request, result)
+	return result, nil
+}
+
+// streamScaffold gera o scaffold em streaming, anunciando cada arquivo assim que
+// ele é recebido por completo, e retorna o texto bruto já completado caso tenha sido truncado
+func streamScaffold(prompt string, opts ScaffoldOptions) (*geminiResult, error) {
+	var progress StreamProgress
+	report := func() {
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+	}
+
+	// Um mesmo caminho é anunciado uma única vez, mesmo que uma continuação recomece o
+	// manifesto do zero e o envie de novo
+	announced := make(map[string]bool)
+	newParser := func() *ManifestStreamParser {
+		return NewManifestStreamParser(func(file StreamedFile) {
+			if announced[file.Path] {
+				return
+			}
+			announced[file.Path] = true
+			progress.Files++
+			if opts.OnFile != nil {
+				opts.OnFile(file)
+			}
+		})
+	}
+	parser := newParser()
+
+	result, err := callGeminiAPIStream(prompt, func(chunk string, tokens int) {
+		parser.Feed(chunk)
+		if tokens > 0 {
+			progress.Tokens = tokens
+		}
+		report()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// As continuações também alimentam o parser, para que os arquivos seguintes sejam anunciados.
+	// Um manifesto recomeçado não continua o texto anterior e vai para um parser novo.
+	return continueTruncatedResponse(userContents(prompt), result, func(chunk string, fresh bool) {
+		if fresh {
+			parser = newParser()
+		}
+		parser.Feed(chunk)
+		report()
+	})
+}
+
+// WriteStreamedFile grava no disco um arquivo recebido durante o streaming,
+// antes que a resposta completa esteja disponível
+// Arquivos copy-from-template e url, .env e arquivos com possíveis segredos são gravados
+// depois, com o projeto completo e já verificado.
+func WriteStreamedFile(projectName string, file StreamedFile) error {
+	entry, err := manifestEntry(file.Path, file.Content)
+	if err != nil {
+		return err
+	}
+	if err := checkManifestPath(entry.Path); err != nil {
+		return err
+	}
+	if !entry.Resolved() || isEnvFile(entry.Path) || fileHasSecrets(entry) {
+		return nil
+	}
+	return writeManifestFile(projectName, entry)
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule é uma linha de um arquivo no formato do .gitignore
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// IgnoreMatcher decide quais caminhos de um projeto devem ser ignorados,
+// seguindo as regras do .gitignore da raiz do projeto
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+// LoadIgnoreMatcher carrega as regras dos arquivos informados, relativos a dir.
+// Arquivos inexistentes são ignorados.
+func LoadIgnoreMatcher(dir string, files ...string) *IgnoreMatcher {
+	matcher := &IgnoreMatcher{}
+	for _, name := range files {
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			matcher.AddPattern(scanner.Text())
+		}
+		file.Close()
+	}
+	return matcher
+}
+
+// AddPattern adiciona uma regra no formato do .gitignore
+func (m *IgnoreMatcher) AddPattern(line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, "\\")
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	// Padrões com barra no início ou no meio valem a partir da raiz do projeto
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return
+	}
+	rule.pattern = line
+	m.rules = append(m.rules, rule)
+}
+
+// Match indica se o caminho relativo (separado por "/") deve ser ignorado.
+// Como em git, a última regra que casar com o caminho prevalece.
+func (m *IgnoreMatcher) Match(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		var matched bool
+		if rule.anchored {
+			matched = matchGlobPath(strings.Split(rule.pattern, "/"), strings.Split(rel, "/"))
+		} else {
+			matched, _ = path.Match(rule.pattern, path.Base(rel))
+		}
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchGlobPath compara segmentos de um padrão com os de um caminho, aceitando "**"
+// como qualquer quantidade de diretórios
+func matchGlobPath(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchGlobPath(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchGlobPath(pattern[1:], parts[1:])
+}
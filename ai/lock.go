@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"zion/config"
+)
+
+// lockFile é o arquivo, dentro do projeto, que registra como o scaffold foi gerado
+const lockFile = ".zion/lock.json"
+
+// LockVersion é a versão atual do formato de lock.json
+const LockVersion = 1
+
+// ScaffoldLock registra a procedência de um projeto gerado: as entradas do scaffold, o
+// prompt efetivo, o modelo, os plugins e a soma sha256 de cada arquivo, para auditar,
+// reproduzir ou atualizar o projeto depois
+type ScaffoldLock struct {
+	Version     int               `json:"lock_version"`
+	ZionVersion string            `json:"zion_version"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at,omitempty"`
+	Inputs      LockInputs        `json:"inputs"`
+	Model       string            `json:"model"`
+	Profile     string            `json:"profile,omitempty"`
+	Prompt      LockPrompt        `json:"prompt"`
+	Plugins     map[string]string `json:"plugins"`
+	Files       map[string]string `json:"files"`
+}
+
+// LockInputs são as opções do scaffold que determinam o projeto gerado
+type LockInputs struct {
+	Name        string   `json:"name"`
+	Language    string   `json:"language"`
+	Framework   string   `json:"framework,omitempty"`
+	Description string   `json:"description"`
+	DocLanguage string   `json:"doc_language,omitempty"`
+	MultiStep   bool     `json:"multi_step,omitempty"`
+	Context     string   `json:"context,omitempty"`
+	Steps       []string `json:"steps,omitempty"`
+	// Registries são os registros passados em --registry para a fixação de versões
+	Registries map[string]string `json:"registries,omitempty"`
+}
+
+// LockPrompt identifica o prompt efetivo enviado ao modelo (após os hooks ModifyPrompt).
+// No modo multi-etapas não há um prompt único e é registrada a árvore aprovada.
+type LockPrompt struct {
+	SHA256        string `json:"sha256,omitempty"`
+	OutlineSHA256 string `json:"outline_sha256,omitempty"`
+}
+
+// NewScaffoldLock monta o lock de um projeto com o modelo e o perfil configurados
+func NewScaffoldLock(zionVersion string, inputs LockInputs, prompt LockPrompt, plugins map[string]string) *ScaffoldLock {
+	cfg := config.LoadConfig()
+	if plugins == nil {
+		plugins = map[string]string{}
+	}
+	if inputs.DocLanguage == "" {
+		inputs.DocLanguage = docLanguage
+	}
+	return &ScaffoldLock{
+		Version:     LockVersion,
+		ZionVersion: zionVersion,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Inputs:      inputs,
+		Model:       cfg.Model,
+		Profile:     cfg.Profile,
+		Prompt:      prompt,
+		Plugins:     plugins,
+		Files:       map[string]string{},
+	}
+}
+
+// HashText retorna a soma sha256 de um texto, no formato usado pelo lock e pelos manifestos
+func HashText(text string) string {
+	return contentChecksum(text)
+}
+
+// RecordFiles registra a soma sha256 dos arquivos do projeto em dir, com as mesmas regras
+// do manifesto do projeto (.gitignore, .zionignore e diretórios como .zion são ignorados)
+func (l *ScaffoldLock) RecordFiles(dir string) error {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	l.Files = make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		l.Files[file.Path] = file.SHA256
+	}
+	return nil
+}
+
+// Save grava o lock em .zion/lock.json dentro do projeto
+func (l *ScaffoldLock) Save(dir string) error {
+	path := filepath.Join(dir, lockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório .zion: %v", err)
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar lock: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("erro ao salvar lock: %v", err)
+	}
+	return nil
+}
+
+// LoadScaffoldLock lê o .zion/lock.json de um projeto
+func LoadScaffoldLock(dir string) (*ScaffoldLock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, lockFile))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler lock: %v", err)
+	}
+	var lock ScaffoldLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("erro ao ler lock: %v", err)
+	}
+	if lock.Version > LockVersion {
+		return nil, fmt.Errorf("versão %d do lock não é suportada (máxima: %d)", lock.Version, LockVersion)
+	}
+	return &lock, nil
+}
@@ -0,0 +1,332 @@
+package ai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"zion/config"
+	"zion/i18n"
+)
+
+// Tipos de conteúdo de um arquivo do manifesto
+const (
+	// ContentText é texto UTF-8 (o padrão)
+	ContentText = "text"
+	// ContentJSON é um documento JSON, gravado com indentação
+	ContentJSON = "json"
+	// ContentBase64 são bytes quaisquer (ícones, fontes, imagens) codificados em base64
+	ContentBase64 = "base64"
+	// ContentTemplate copia o arquivo Source do diretório de templates
+	ContentTemplate = "copy-from-template"
+	// ContentURL baixa o arquivo de Source ao gravar o projeto
+	ContentURL = "url"
+)
+
+// ContentTypes são os tipos aceitos no campo type de um arquivo
+var ContentTypes = []string{ContentText, ContentJSON, ContentBase64, ContentTemplate, ContentURL}
+
+// maxFetchedFile limita o tamanho de um arquivo baixado de uma URL
+const maxFetchedFile = 32 << 20
+
+// fetchTimeout limita o tempo do download de cada arquivo
+const fetchTimeout = 60 * time.Second
+
+// fileEntryKeys são as chaves de uma entrada tipada na resposta do modelo
+var fileEntryKeys = map[string]bool{
+	"type": true, "content": true, "mode": true, "url": true, "template": true, "sha256": true,
+}
+
+// orderedValue decodifica o valor de um arquivo na resposta do modelo: strings viram texto
+// e objetos e arrays ficam como json.RawMessage, com as chaves na ordem em que o modelo as
+// escreveu (decodificar para map perderia essa ordem)
+func orderedValue(raw json.RawMessage) interface{} {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return raw
+}
+
+// textContent indica se o conteúdo de um arquivo veio como string na resposta do modelo,
+// direto ou no campo content de uma entrada tipada
+func textContent(value interface{}) bool {
+	if _, ok := value.(string); ok {
+		return true
+	}
+	if entry, ok := typedEntry(value); ok {
+		_, ok = entry["content"].(string)
+		return ok
+	}
+	return false
+}
+
+// orderedFields decodifica um objeto JSON campo a campo com orderedValue
+func orderedFields(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case json.RawMessage:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(v, &fields); err != nil {
+			return nil, false
+		}
+		entry := make(map[string]interface{}, len(fields))
+		for key, raw := range fields {
+			entry[key] = orderedValue(raw)
+		}
+		return entry, true
+	}
+	return nil, false
+}
+
+// typedEntry indica se o valor de um arquivo na resposta do modelo é uma entrada tipada,
+// como {"type": "base64", "content": "..."} ou {"content": "...", "mode": "0755"}, e não o
+// conteúdo de um arquivo JSON
+func typedEntry(value interface{}) (map[string]interface{}, bool) {
+	entry, ok := orderedFields(value)
+	if !ok {
+		return nil, false
+	}
+	_, hasType := entry["type"].(string)
+	_, hasMode := entry["mode"].(string)
+	if !hasType && !hasMode {
+		return nil, false
+	}
+	if hasType && !isContentType(entry["type"].(string)) {
+		return nil, false
+	}
+	for key := range entry {
+		if !fileEntryKeys[key] {
+			return nil, false
+		}
+	}
+	return entry, true
+}
+
+func isContentType(contentType string) bool {
+	for _, t := range ContentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestEntry converte o valor de um arquivo na resposta do modelo em arquivo do manifesto:
+// uma string ou um objeto JSON, como antes, ou uma entrada tipada
+func manifestEntry(filePath string, value interface{}) (ManifestFile, error) {
+	entry, ok := typedEntry(value)
+	if !ok {
+		text, err := fileContentString(filePath, value)
+		if err != nil {
+			return ManifestFile{}, fmt.Errorf("erro ao serializar conteúdo de '%s': %v", filePath, err)
+		}
+		return ManifestFile{Path: filePath, Content: text}, nil
+	}
+
+	file := ManifestFile{Path: filePath}
+	file.Type, _ = entry["type"].(string)
+	file.Mode, _ = entry["mode"].(string)
+	file.SHA256, _ = entry["sha256"].(string)
+	switch file.Type {
+	case ContentTemplate:
+		file.Source, _ = entry["template"].(string)
+	case ContentURL:
+		file.Source, _ = entry["url"].(string)
+	case ContentBase64:
+		file.Content, _ = entry["content"].(string)
+		file.Content = strings.Join(strings.Fields(file.Content), "")
+	default:
+		text, err := fileContentString(filePath, entry["content"])
+		if err != nil {
+			return ManifestFile{}, fmt.Errorf("erro ao serializar conteúdo de '%s': %v", filePath, err)
+		}
+		file.Content = text
+		if file.Type == ContentText {
+			file.Type = ""
+		}
+	}
+	return file, file.check()
+}
+
+// check confere o tipo, o modo e a origem de um arquivo do manifesto
+func (f ManifestFile) check() error {
+	if f.Type != "" && !isContentType(f.Type) {
+		return fmt.Errorf("tipo de conteúdo desconhecido em %s: %s (%s)", f.Path, f.Type, strings.Join(ContentTypes, ", "))
+	}
+	if _, err := f.FileMode(); err != nil {
+		return err
+	}
+	switch f.Type {
+	case ContentBase64:
+		if _, err := base64.StdEncoding.DecodeString(f.Content); err != nil {
+			return fmt.Errorf("conteúdo base64 inválido em %s: %v", f.Path, err)
+		}
+	case ContentTemplate:
+		if f.Source == "" {
+			return fmt.Errorf("%s: copy-from-template sem o template de origem", f.Path)
+		}
+		if err := checkManifestPath(f.Source); err != nil {
+			return fmt.Errorf("%s: template de origem inválido: %v", f.Path, err)
+		}
+	case ContentURL:
+		if !strings.HasPrefix(f.Source, "https://") && !strings.HasPrefix(f.Source, "http://") {
+			return fmt.Errorf("%s: URL inválida: %q", f.Path, f.Source)
+		}
+	}
+	return nil
+}
+
+// FileMode retorna as permissões do arquivo (0644 quando o modo não é informado)
+func (f ManifestFile) FileMode() (os.FileMode, error) {
+	if f.Mode == "" {
+		return 0644, nil
+	}
+	mode, err := strconv.ParseUint(f.Mode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("modo inválido em %s: %q (use octal, como 0755)", f.Path, f.Mode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// Resolved indica se o conteúdo do arquivo está no manifesto; copy-from-template e url
+// só têm conteúdo depois de Resolve
+func (f ManifestFile) Resolved() bool {
+	return f.Type != ContentTemplate && f.Type != ContentURL
+}
+
+// Bytes retorna o conteúdo do arquivo como gravado no disco
+func (f ManifestFile) Bytes() ([]byte, error) {
+	switch f.Type {
+	case ContentBase64:
+		return base64.StdEncoding.DecodeString(f.Content)
+	case ContentTemplate, ContentURL:
+		return nil, fmt.Errorf("o conteúdo de %s ainda não foi obtido de %s", f.Path, f.Source)
+	}
+	return []byte(f.Content), nil
+}
+
+// setBytes guarda o conteúdo do arquivo como texto ou, se não for UTF-8, em base64
+func (f *ManifestFile) setBytes(data []byte) {
+	if utf8.Valid(data) {
+		f.Type, f.Content = "", string(data)
+	} else {
+		f.Type, f.Content = ContentBase64, base64.StdEncoding.EncodeToString(data)
+	}
+	f.SHA256 = contentChecksum(string(data))
+}
+
+// TemplateDirs retorna os diretórios onde são procurados os templates de copy-from-template:
+// os informados e o diretório de templates do Zion (~/.zion/templates)
+func TemplateDirs(extra ...string) []string {
+	return append(extra, config.LoadConfig().TemplatesDir)
+}
+
+// Resolve obtém o conteúdo dos arquivos copy-from-template (procurados nos diretórios de
+// templates) e url (baixados), conferindo a soma sha256 quando informada. Os arquivos que
+// não puderam ser obtidos saem do manifesto e são retornados como erros.
+func (m *ProjectManifest) Resolve(templateDirs []string) []error {
+	var problems []error
+	var client *http.Client
+	files := m.Files[:0]
+	for _, file := range m.Files {
+		if file.Resolved() {
+			files = append(files, file)
+			continue
+		}
+
+		var data []byte
+		var err error
+		if file.Type == ContentTemplate {
+			data, err = readTemplate(templateDirs, file.Source)
+		} else {
+			if client == nil {
+				client = &http.Client{Timeout: fetchTimeout}
+			}
+			fmt.Print(i18n.T("ai.fetching_file", file.Path, file.Source))
+			data, err = fetchURL(client, file.Source)
+		}
+		if err == nil && file.SHA256 != "" && !strings.EqualFold(file.SHA256, contentChecksum(string(data))) {
+			err = fmt.Errorf("o conteúdo de %s não confere com a soma sha256 do manifesto", file.Source)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %v", file.Path, err))
+			continue
+		}
+		file.setBytes(data)
+		files = append(files, file)
+	}
+	m.Files = files
+	return problems
+}
+
+// readTemplate lê um template do primeiro diretório que o contém
+func readTemplate(dirs []string, source string) ([]byte, error) {
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(source)))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("template não encontrado: %s", source)
+}
+
+// fetchURL baixa um arquivo
+func fetchURL(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro ao baixar %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchedFile+1))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar %s: %v", url, err)
+	}
+	if len(data) > maxFetchedFile {
+		return nil, fmt.Errorf("%s excede o limite de %d MB", url, maxFetchedFile>>20)
+	}
+	return data, nil
+}
+
+// writeManifestFile grava um arquivo do manifesto no diretório do projeto, com o modo informado
+func writeManifestFile(dir string, file ManifestFile) error {
+	data, err := file.Bytes()
+	if err != nil {
+		return err
+	}
+	mode, err := file.FileMode()
+	if err != nil {
+		return err
+	}
+	fullPath := filepath.Join(dir, filepath.FromSlash(file.Path))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório pai para '%s': %v", file.Path, err)
+	}
+	if err := os.WriteFile(fullPath, data, mode); err != nil {
+		return fmt.Errorf("erro ao criar arquivo '%s': %v", file.Path, err)
+	}
+	// WriteFile não altera o modo de um arquivo existente e aplica a umask
+	if file.Mode != "" {
+		if err := os.Chmod(fullPath, mode); err != nil {
+			return fmt.Errorf("erro ao definir o modo de '%s': %v", file.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+	"zion/i18n"
+)
+
+// Tipos de alteração entre duas versões de um manifesto
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// FileChange descreve a alteração de um arquivo entre dois manifestos
+type FileChange struct {
+	Path       string
+	Kind       string
+	OldContent string
+	NewContent string
+	// Mode são as permissões do arquivo gravado; zero mantém o padrão
+	Mode os.FileMode
+}
+
+// Diff retorna o diff unificado da alteração; arquivos binários aparecem só com o tamanho
+func (c FileChange) Diff() string {
+	if !utf8.ValidString(c.OldContent) || !utf8.ValidString(c.NewContent) {
+		return fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", c.Path, c.Path, i18n.T("ai.binary_diff", len(c.OldContent), len(c.NewContent)))
+	}
+	return UnifiedDiff(c.Path, c.OldContent, c.NewContent)
+}
+
+// Stat retorna o número de linhas adicionadas e removidas pela alteração
+func (c FileChange) Stat() (added, removed int) {
+	if !utf8.ValidString(c.OldContent) || !utf8.ValidString(c.NewContent) {
+		return 0, 0
+	}
+	return DiffStat(c.OldContent, c.NewContent)
+}
+
+// resolvedManifest decodifica um manifesto já processado e obtém os arquivos
+// copy-from-template e url
+func resolvedManifest(manifest string) (*ProjectManifest, error) {
+	m, err := ManifestFromResponse("", manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, problem := range m.Resolve(TemplateDirs()) {
+		fmt.Printf("⚠️  %v\n", problem)
+	}
+	return m, nil
+}
+
+// DiffManifests compara dois manifestos e retorna os arquivos adicionados, removidos e
+// modificados, com o modo informado no novo manifesto
+func DiffManifests(oldManifest, newManifest string) ([]FileChange, error) {
+	oldM, err := resolvedManifest(oldManifest)
+	if err != nil {
+		return nil, err
+	}
+	newM, err := resolvedManifest(newManifest)
+	if err != nil {
+		return nil, err
+	}
+	changes := diffFileMaps(oldM.FileMap(), newM.FileMap())
+	setChangeModes(changes, newM)
+	return changes, nil
+}
+
+// setChangeModes copia para as alterações o modo dos arquivos que o informam no manifesto;
+// os demais ficam com zero, que mantém o modo de um arquivo existente
+func setChangeModes(changes []FileChange, m *ProjectManifest) {
+	modes := make(map[string]os.FileMode)
+	for _, file := range m.Files {
+		if file.Mode != "" {
+			modes[file.Path], _ = file.FileMode()
+		}
+	}
+	for i := range changes {
+		if changes[i].Kind != ChangeRemoved {
+			changes[i].Mode = modes[changes[i].Path]
+		}
+	}
+}
+
+// DiffProject compara o manifesto gerado com os arquivos atuais do projeto em dir: arquivos
+// adicionados e modificados são os que o projeto tem a mais ou diferentes da geração
+func DiffProject(dir string, generated *ProjectManifest) ([]FileChange, error) {
+	current, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	return diffFileMaps(generated.FileMap(), current.FileMap()), nil
+}
+
+// diffFileMaps compara dois conjuntos de arquivos, em ordem de caminho
+func diffFileMaps(oldFiles, newFiles map[string]string) []FileChange {
+	var changes []FileChange
+	for path, newContent := range newFiles {
+		oldContent, exists := oldFiles[path]
+		switch {
+		case !exists:
+			changes = append(changes, FileChange{Path: path, Kind: ChangeAdded, NewContent: newContent})
+		case oldContent != newContent:
+			changes = append(changes, FileChange{Path: path, Kind: ChangeModified, OldContent: oldContent, NewContent: newContent})
+		}
+	}
+	for path, oldContent := range oldFiles {
+		if _, exists := newFiles[path]; !exists {
+			changes = append(changes, FileChange{Path: path, Kind: ChangeRemoved, OldContent: oldContent})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// ApplyFileChanges grava no diretório do projeto os arquivos adicionados e modificados
+// e remove os arquivos excluídos. Antes, os arquivos passam pela verificação de segredos
+// (ScanChanges), que pode alterá-los ou interromper a gravação.
+func ApplyFileChanges(dir string, changes []FileChange) error {
+	changes, report, err := ScanChanges(dir, changes)
+	if report != nil {
+		printSecretReport(report)
+	}
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if change.Kind == ChangeRemoved {
+			fmt.Print(i18n.T("ai.removing_file", change.Path))
+			if err := os.Remove(filepath.Join(dir, change.Path)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("erro ao remover arquivo %s: %v", change.Path, err)
+			}
+			continue
+		}
+		if err := CreateFile(dir, change.Path, change.NewContent); err != nil {
+			return err
+		}
+		if change.Mode != 0 {
+			if err := os.Chmod(filepath.Join(dir, change.Path), change.Mode); err != nil {
+				return fmt.Errorf("erro ao definir o modo de %s: %v", change.Path, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,522 @@
+package ai
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestVersion é a versão atual do formato de manifesto
+const ManifestVersion = 1
+
+// Formatos de arquivo do manifesto
+const (
+	ManifestJSON = "json"
+	ManifestYAML = "yaml"
+	ManifestTOML = "toml"
+)
+
+// ManifestFormats são os formatos aceitos pelas flags --format
+var ManifestFormats = []string{ManifestJSON, ManifestYAML, ManifestTOML}
+
+// ProjectManifest é o manifesto de um projeto: os diretórios e o conteúdo completo de cada
+// arquivo, com metadados. Pode ser gravado e lido de novo em JSON, YAML ou TOML.
+type ProjectManifest struct {
+	Version     int             `json:"version" yaml:"version" toml:"version"`
+	Project     ManifestProject `json:"project" yaml:"project" toml:"project"`
+	Directories []string        `json:"directories" yaml:"directories" toml:"directories"`
+	Files       []ManifestFile  `json:"files" yaml:"files" toml:"files"`
+}
+
+// ManifestProject são os metadados do projeto descrito pelo manifesto
+type ManifestProject struct {
+	Name        string `json:"name" yaml:"name" toml:"name"`
+	Language    string `json:"language,omitempty" yaml:"language,omitempty" toml:"language,omitempty"`
+	Framework   string `json:"framework,omitempty" yaml:"framework,omitempty" toml:"framework,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Generator   string `json:"generator,omitempty" yaml:"generator,omitempty" toml:"generator,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty" yaml:"created_at,omitempty" toml:"created_at,omitempty"`
+}
+
+// ManifestFile é um arquivo do manifesto. Type indica como Content é interpretado (texto,
+// por padrão, ou um dos ContentTypes); em copy-from-template e url, Source é o template ou
+// a URL de origem. Mode são as permissões em octal, como 0755 para scripts. SHA256,
+// quando informado, é a soma dos bytes gravados no disco e é conferido na leitura.
+type ManifestFile struct {
+	Path    string `json:"path" yaml:"path" toml:"path"`
+	Type    string `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty"`
+	Mode    string `json:"mode,omitempty" yaml:"mode,omitempty" toml:"mode,omitempty"`
+	Source  string `json:"source,omitempty" yaml:"source,omitempty" toml:"source,omitempty"`
+	SHA256  string `json:"sha256,omitempty" yaml:"sha256,omitempty" toml:"sha256,omitempty"`
+	Content string `json:"content,omitempty" yaml:"content,omitempty" toml:"content,omitempty"`
+}
+
+// MarshalYAML grava o conteúdo de várias linhas como bloco literal (|) sempre que o bloco
+// é lido de volta sem alteração; nos demais casos usa string entre aspas duplas
+func (f ManifestFile) MarshalYAML() (interface{}, error) {
+	content := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.Content}
+	if strings.Contains(f.Content, "\n") {
+		content.Style = yaml.DoubleQuotedStyle
+		if yamlLiteralSafe(f.Content) {
+			content.Style = yaml.LiteralStyle
+		}
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range [][2]string{
+		{"path", f.Path}, {"type", f.Type}, {"mode", f.Mode}, {"source", f.Source}, {"sha256", f.SHA256},
+	} {
+		if field[1] != "" || field[0] == "path" {
+			node.Content = append(node.Content, yamlString(field[0]), yamlString(field[1]))
+		}
+	}
+	if f.Content != "" || f.Resolved() {
+		node.Content = append(node.Content, yamlString("content"), content)
+	}
+	return node, nil
+}
+
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// yamlLiteralSafe indica se o texto sobrevive a um bloco literal: sem caracteres de
+// controle além de tab e sem espaço ou quebra de linha no início
+func yamlLiteralSafe(s string) bool {
+	if s == "" || strings.ContainsAny(s[:1], " \t\n") {
+		return false
+	}
+	for _, r := range s {
+		if (isTOMLControl(r) && r != '\n' && r != '\t') || r == '\uFEFF' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewProjectManifest monta o manifesto de um projeto a partir dos diretórios e do conteúdo
+// dos arquivos, em ordem de caminho
+func NewProjectManifest(name string, directories []string, files map[string]string) *ProjectManifest {
+	m := &ProjectManifest{
+		Version: ManifestVersion,
+		Project: ManifestProject{
+			Name:      name,
+			Generator: "zion",
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+		Directories: append([]string{}, directories...),
+		Files:       []ManifestFile{},
+	}
+	sort.Strings(m.Directories)
+	for filePath, content := range files {
+		m.Files = append(m.Files, ManifestFile{Path: filePath, SHA256: contentChecksum(content), Content: content})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	return m
+}
+
+// ReadManifest monta o manifesto de um projeto existente no disco, com as mesmas regras
+// de ReadProjectManifest. Arquivos que não são UTF-8 entram em base64 e arquivos
+// executáveis guardam o modo.
+func ReadManifest(dir string) (*ProjectManifest, error) {
+	var directories []string
+	var files []ManifestFile
+	err := walkProjectTree(dir, func(rel string) {
+		directories = append(directories, rel)
+	}, func(rel string, data []byte, mode os.FileMode) error {
+		file := ManifestFile{Path: rel}
+		file.setBytes(data)
+		if mode&0111 != 0 {
+			file.Mode = fmt.Sprintf("%04o", mode.Perm())
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler projeto: %v", err)
+	}
+	name := filepath.Base(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		name = filepath.Base(abs)
+	}
+	m := NewProjectManifest(name, directories, nil)
+	m.Files = files
+	return m, nil
+}
+
+// ManifestFromResponse converte a resposta de scaffold do modelo ({"structure": ...}) em manifesto.
+// Caminhos absolutos ou que saem do diretório do projeto são recusados.
+func ManifestFromResponse(name, response string) (*ProjectManifest, error) {
+	response = strings.TrimSpace(response)
+	if strings.HasPrefix(response, "```json\n") && strings.HasSuffix(response, "\n```") {
+		response = strings.TrimSuffix(strings.TrimPrefix(response, "```json\n"), "\n```")
+	}
+	var scaffoldResp rawScaffoldResponse
+	if err := json.Unmarshal([]byte(response), &scaffoldResp); err != nil {
+		return nil, fmt.Errorf("erro ao fazer parse do manifesto: %v", err)
+	}
+	m := NewProjectManifest(name, scaffoldResp.Structure.Directories, nil)
+	// Arquivos recebidos como string, para o fallback de respostas escapadas duas vezes
+	texts := make(map[string]string)
+	for filePath, value := range scaffoldResp.Structure.Files {
+		content := orderedValue(value)
+		file, err := manifestEntry(filePath, content)
+		if err != nil {
+			return nil, err
+		}
+		if file.Type == "" && textContent(content) {
+			texts[file.Path] = file.Content
+		}
+		m.Files = append(m.Files, file)
+	}
+	decoded := make(map[string]bool)
+	for _, filePath := range decodeDoubleEscaped(texts) {
+		decoded[filePath] = true
+	}
+	for i := range m.Files {
+		file := &m.Files[i]
+		if decoded[file.Path] {
+			file.Content = texts[file.Path]
+			if isPackageJSON(file.Path) {
+				file.Content = normalizePackageJSON(file.Content)
+			}
+		}
+		if file.SHA256 == "" && file.Resolved() {
+			data, _ := file.Bytes()
+			file.SHA256 = contentChecksum(string(data))
+		}
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FileMap retorna o conteúdo dos arquivos do manifesto por caminho, como gravado no disco.
+// Arquivos copy-from-template e url ainda não resolvidos ficam de fora.
+func (m *ProjectManifest) FileMap() map[string]string {
+	files := make(map[string]string, len(m.Files))
+	for _, file := range m.Files {
+		if data, err := file.Bytes(); err == nil {
+			files[file.Path] = string(data)
+		}
+	}
+	return files
+}
+
+func contentChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// validate confere a versão do formato, os caminhos e as somas de verificação do manifesto.
+// Caminhos absolutos ou que saem do diretório do projeto são recusados.
+func (m *ProjectManifest) validate() error {
+	if m.Version > ManifestVersion {
+		return fmt.Errorf("versão %d do manifesto não é suportada (máxima: %d)", m.Version, ManifestVersion)
+	}
+	seen := make(map[string]bool)
+	for _, dir := range m.Directories {
+		if err := checkManifestPath(dir); err != nil {
+			return err
+		}
+	}
+	for _, file := range m.Files {
+		if err := checkManifestPath(file.Path); err != nil {
+			return err
+		}
+		if seen[file.Path] {
+			return fmt.Errorf("arquivo repetido no manifesto: %s", file.Path)
+		}
+		seen[file.Path] = true
+		if err := file.check(); err != nil {
+			return err
+		}
+		if !file.Resolved() || file.SHA256 == "" {
+			continue
+		}
+		if data, _ := file.Bytes(); !strings.EqualFold(file.SHA256, contentChecksum(string(data))) {
+			return fmt.Errorf("o conteúdo de %s não confere com a soma sha256 do manifesto", file.Path)
+		}
+	}
+	return nil
+}
+
+func checkManifestPath(p string) error {
+	clean := path.Clean(filepath.ToSlash(p))
+	if p == "" || path.IsAbs(clean) || filepath.IsAbs(p) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("caminho inválido no manifesto: %q", p)
+	}
+	return nil
+}
+
+// ManifestFormatFor retorna o formato do manifesto pela extensão do arquivo
+func ManifestFormatFor(file string) (string, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return ManifestJSON, nil
+	case ".yaml", ".yml":
+		return ManifestYAML, nil
+	case ".toml":
+		return ManifestTOML, nil
+	}
+	return "", fmt.Errorf("não foi possível identificar o formato do manifesto %s; use --format (%s)", file, strings.Join(ManifestFormats, ", "))
+}
+
+// Encode serializa o manifesto no formato informado
+func (m *ProjectManifest) Encode(format string) ([]byte, error) {
+	switch format {
+	case ManifestJSON:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(m); err != nil {
+			return nil, fmt.Errorf("erro ao gerar manifesto JSON: %v", err)
+		}
+		return buf.Bytes(), nil
+	case ManifestYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(m); err != nil {
+			return nil, fmt.Errorf("erro ao gerar manifesto YAML: %v", err)
+		}
+		encoder.Close()
+		return buf.Bytes(), nil
+	case ManifestTOML:
+		return encodeManifestTOML(m), nil
+	}
+	return nil, fmt.Errorf("formato de manifesto desconhecido: %s (%s)", format, strings.Join(ManifestFormats, ", "))
+}
+
+// encodeManifestTOML escreve o manifesto em TOML com o conteúdo dos arquivos em strings
+// de várias linhas, que continuam legíveis e são lidas de volta sem alteração
+func encodeManifestTOML(m *ProjectManifest) []byte {
+	var b strings.Builder
+	b.WriteString("# Manifesto de projeto gerado pelo Zion\n")
+	fmt.Fprintf(&b, "version = %d\n", m.Version)
+	b.WriteString("directories = [")
+	for i, dir := range m.Directories {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  " + tomlBasicString(dir))
+	}
+	if len(m.Directories) > 0 {
+		b.WriteString(",\n")
+	}
+	b.WriteString("]\n\n[project]\n")
+	for _, field := range [][2]string{
+		{"name", m.Project.Name},
+		{"language", m.Project.Language},
+		{"framework", m.Project.Framework},
+		{"description", m.Project.Description},
+		{"generator", m.Project.Generator},
+		{"created_at", m.Project.CreatedAt},
+	} {
+		if field[1] != "" || field[0] == "name" {
+			fmt.Fprintf(&b, "%s = %s\n", field[0], tomlBasicString(field[1]))
+		}
+	}
+	for _, file := range m.Files {
+		b.WriteString("\n[[files]]\n")
+		for _, field := range [][2]string{
+			{"path", file.Path}, {"type", file.Type}, {"mode", file.Mode}, {"source", file.Source}, {"sha256", file.SHA256},
+		} {
+			if field[1] != "" || field[0] == "path" {
+				fmt.Fprintf(&b, "%s = %s\n", field[0], tomlBasicString(field[1]))
+			}
+		}
+		if file.Content == "" && !file.Resolved() {
+			continue
+		}
+		if strings.Contains(file.Content, "\n") {
+			fmt.Fprintf(&b, "content = %s\n", tomlMultilineString(file.Content))
+		} else {
+			fmt.Fprintf(&b, "content = %s\n", tomlBasicString(file.Content))
+		}
+	}
+	return []byte(b.String())
+}
+
+// tomlEscapeRune escreve a sequência de escape TOML de um caractere de controle
+func tomlEscapeRune(b *strings.Builder, r rune) {
+	switch r {
+	case '\b':
+		b.WriteString(`\b`)
+	case '\t':
+		b.WriteString(`\t`)
+	case '\n':
+		b.WriteString(`\n`)
+	case '\f':
+		b.WriteString(`\f`)
+	case '\r':
+		b.WriteString(`\r`)
+	default:
+		fmt.Fprintf(b, `\u%04X`, r)
+	}
+}
+
+func isTOMLControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// tomlBasicString escreve s como string TOML de uma linha
+func tomlBasicString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case isTOMLControl(r):
+			tomlEscapeRune(&b, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlMultilineString escreve s como string TOML de várias linhas ("""). A quebra de linha
+// logo após a abertura é descartada pelo leitor, então o conteúdo volta exatamente igual.
+// Aspas só são escapadas quando formariam o delimitador ou encostariam no fechamento.
+func tomlMultilineString(s string) string {
+	var b strings.Builder
+	b.WriteString("\"\"\"\n")
+	runes := []rune(s)
+	quotes := 0
+	for i, r := range runes {
+		switch {
+		case r == '"':
+			if quotes == 2 || i == len(runes)-1 {
+				b.WriteString(`\"`)
+				quotes = 0
+				continue
+			}
+			quotes++
+			b.WriteRune(r)
+			continue
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case isTOMLControl(r):
+			tomlEscapeRune(&b, r)
+		default:
+			b.WriteRune(r)
+		}
+		quotes = 0
+	}
+	b.WriteString("\"\"\"")
+	return b.String()
+}
+
+// DecodeManifest lê um manifesto no formato informado. Com format vazio, o formato é
+// deduzido do conteúdo. A resposta de scaffold do modelo ({"structure": ...}) também é aceita.
+func DecodeManifest(data []byte, format string) (*ProjectManifest, error) {
+	if format == "" {
+		format = sniffManifestFormat(data)
+	}
+
+	m := &ProjectManifest{}
+	var err error
+	switch format {
+	case ManifestJSON:
+		var probe struct {
+			Structure json.RawMessage `json:"structure"`
+		}
+		if json.Unmarshal(data, &probe) == nil && probe.Structure != nil {
+			return ManifestFromResponse("", string(data))
+		}
+		err = json.Unmarshal(data, m)
+	case ManifestYAML:
+		err = yaml.Unmarshal(data, m)
+	case ManifestTOML:
+		err = toml.Unmarshal(data, m)
+	default:
+		return nil, fmt.Errorf("formato de manifesto desconhecido: %s (%s)", format, strings.Join(ManifestFormats, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto %s: %v", strings.ToUpper(format), err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// sniffManifestFormat deduz o formato de um manifesto sem extensão conhecida
+func sniffManifestFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return ManifestJSON
+	}
+	var m ProjectManifest
+	if toml.Unmarshal(data, &m) == nil {
+		return ManifestTOML
+	}
+	return ManifestYAML
+}
+
+// ReadManifestFile lê um manifesto de um arquivo. Com format vazio, o formato vem da
+// extensão ou, se ela não for conhecida, do conteúdo.
+func ReadManifestFile(file, format string) (*ProjectManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto: %v", err)
+	}
+	if format == "" {
+		format, _ = ManifestFormatFor(file)
+	}
+	return DecodeManifest(data, format)
+}
+
+// WriteManifestFile grava o manifesto em um arquivo. Com format vazio, o formato vem da extensão.
+func WriteManifestFile(file, format string, m *ProjectManifest) error {
+	if format == "" {
+		var err error
+		if format, err = ManifestFormatFor(file); err != nil {
+			return err
+		}
+	}
+	data, err := m.Encode(format)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório do manifesto: %v", err)
+		}
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("erro ao gravar manifesto: %v", err)
+	}
+	return nil
+}
+
+// ManifestChanges compara os arquivos do manifesto com o projeto em dir e retorna os
+// arquivos que seriam criados ou alterados, com o modo de cada um. Arquivos que só existem
+// no disco são mantidos. Arquivos copy-from-template e url devem ser resolvidos antes.
+func ManifestChanges(dir string, m *ProjectManifest) ([]FileChange, error) {
+	changes, err := diskChanges(dir, m.FileMap())
+	if err != nil {
+		return nil, err
+	}
+	setChangeModes(changes, m)
+	return changes, nil
+}
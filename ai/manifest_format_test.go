@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+// roundTripContents são conteúdos que costumam quebrar a serialização em YAML ou TOML
+var roundTripContents = map[string]string{
+	"vazio.txt":             "",
+	"linha.txt":             "uma linha",
+	"final.txt":             "sem quebra final\nsegunda linha",
+	"espacos.txt":           "  recuo no início\nfim com espaço \n",
+	"quebra_inicial.txt":    "\nprimeira linha em branco\n",
+	"quebras_finais.txt":    "texto\n\n\n",
+	"tabs.go":               "package main\n\nfunc main() {\n\tprintln(\"oi\")\n}\n",
+	"crlf.bat":              "@echo off\r\necho oi\r\n",
+	"cr.txt":                "a\rb\r",
+	"aspas.py":              "s = \"\"\"texto\"\"\"\nt = '''outro'''\nu = \"\"\"\"\"\"\n",
+	"barras.txt":            "C:\\caminho\\novo\n\\n literal\n\\\"\n",
+	"unicode.md":            "# Olá, 世界 🚀\n",
+	"controle.txt":          "a\x00b\x1bc\x7f\n",
+	"bom.txt":               "\ufeffcom BOM\n",
+	"yaml.yml":              "---\nchave: valor\n# comentário\nlista:\n  - a\n...\n",
+	"toml.toml":             "[secao]\nchave = \"valor\"\n",
+	"marcadores.txt":        "%s %d {{.Nome}} ${VAR}\n",
+	"fim_com_aspas.txt":     "termina com aspas\"",
+	"fim_com_barra.txt":     "termina com barra\\",
+	"chaves_yaml.txt":       "|\n>\n- item\n? chave\n",
+	"so_espacos.txt":        "   ",
+	"tab_inicial.txt":       "\tcomeça com tab\n",
+	"dir/sub/arquivo.txt":   "aninhado\n",
+	"nome com espaços.txt":  "ok\n",
+	"caminho.unicode/ç.txt": "ç\n",
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	m := NewProjectManifest("projeto", []string{"dir", "dir/sub", "caminho.unicode"}, roundTripContents)
+	m.Project.Language = "go"
+	m.Project.Description = "descrição com \"aspas\"\ne duas linhas"
+	m.Files = append(m.Files,
+		ManifestFile{Path: "icone.png", Type: ContentBase64, Content: "iVBORw0KGgo="},
+		ManifestFile{Path: "script.sh", Mode: "0755", Content: "#!/bin/sh\necho oi\n"},
+		ManifestFile{Path: "LICENSE", Type: ContentTemplate, Source: "licenses/MIT"},
+		ManifestFile{Path: "logo.svg", Type: ContentURL, Source: "https://example.com/logo.svg"},
+	)
+
+	for _, format := range ManifestFormats {
+		for _, sniff := range []bool{false, true} {
+			name := format
+			if sniff {
+				name += " (formato deduzido)"
+			}
+			t.Run(name, func(t *testing.T) {
+				data, err := m.Encode(format)
+				if err != nil {
+					t.Fatal(err)
+				}
+				decodeFormat := format
+				if sniff {
+					decodeFormat = ""
+				}
+				decoded, err := DecodeManifest(data, decodeFormat)
+				if err != nil {
+					t.Fatalf("%v\n%s", err, data)
+				}
+
+				if !reflect.DeepEqual(decoded.Project, m.Project) {
+					t.Errorf("projeto = %+v, esperado %+v", decoded.Project, m.Project)
+				}
+				if !reflect.DeepEqual(decoded.Directories, m.Directories) {
+					t.Errorf("diretórios = %q, esperado %q", decoded.Directories, m.Directories)
+				}
+				want := make(map[string]ManifestFile)
+				for _, file := range m.Files {
+					want[file.Path] = file
+				}
+				got := make(map[string]ManifestFile)
+				for _, file := range decoded.Files {
+					got[file.Path] = file
+				}
+				for path, file := range want {
+					if !reflect.DeepEqual(got[path], file) {
+						t.Errorf("%s = %+v, esperado %+v", path, got[path], file)
+					}
+				}
+				if len(got) != len(want) {
+					t.Errorf("%d arquivos, esperado %d", len(got), len(want))
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeManifestScaffoldResponse(t *testing.T) {
+	m, err := DecodeManifest([]byte(`{"structure":{"directories":["src"],"files":{"src/a.txt":"a\n"}}}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.FileMap(); !reflect.DeepEqual(got, map[string]string{"src/a.txt": "a\n"}) {
+		t.Errorf("arquivos = %q", got)
+	}
+}
+
+func TestDecodeManifestRejects(t *testing.T) {
+	tests := []struct {
+		name, format, data string
+	}{
+		{"caminho absoluto", ManifestYAML, "files:\n  - path: /etc/passwd\n    content: x\n"},
+		{"caminho fora do projeto", ManifestTOML, "[[files]]\npath = \"../x\"\ncontent = \"x\"\n"},
+		{"arquivo repetido", ManifestJSON, `{"files":[{"path":"a","content":"1"},{"path":"a","content":"2"}]}`},
+		{"sha256 divergente", ManifestJSON, `{"files":[{"path":"a","content":"1","sha256":"00"}]}`},
+		{"versão futura", ManifestYAML, "version: 99\nfiles: []\n"},
+		{"tipo desconhecido", ManifestYAML, "files:\n  - path: a\n    type: zip\n"},
+		{"formato desconhecido", "xml", "<files/>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeManifest([]byte(tt.data), tt.format); err == nil {
+				t.Error("manifesto aceito, esperado erro")
+			}
+		})
+	}
+}
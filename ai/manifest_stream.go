@@ -0,0 +1,231 @@
+package ai
+
+import (
+	"encoding/json"
+)
+
+// StreamedFile representa um arquivo do manifesto que já foi recebido por completo
+type StreamedFile struct {
+	Path    string
+	Content interface{}
+}
+
+// streamFrame representa um objeto ou array aberto durante a leitura incremental
+type streamFrame struct {
+	object    bool
+	start     int    // posição do caractere de abertura no buffer
+	key       string // chave sob a qual este container está no pai
+	curKey    string // última chave lida dentro deste objeto
+	expectKey bool
+}
+
+// ManifestStreamParser lê o JSON do manifesto aos poucos e anuncia cada arquivo
+// de "structure.files" assim que o valor correspondente é fechado
+type ManifestStreamParser struct {
+	// OnFile é chamado para cada arquivo completo encontrado
+	OnFile func(file StreamedFile)
+
+	buf     []byte
+	pos     int
+	started bool
+	stack   []streamFrame
+
+	inString  bool
+	escaped   bool
+	strStart  int
+	strIsKey  bool
+	fileName  string
+	fileStart int
+	fileDepth int
+
+	files       []StreamedFile
+	directories []string
+}
+
+// NewManifestStreamParser cria um parser incremental que chama onFile para cada arquivo completo
+func NewManifestStreamParser(onFile func(file StreamedFile)) *ManifestStreamParser {
+	return &ManifestStreamParser{OnFile: onFile, fileStart: -1}
+}
+
+// Files retorna os arquivos completos encontrados até agora
+func (p *ManifestStreamParser) Files() []StreamedFile {
+	return p.files
+}
+
+// Write alimenta o parser com mais um trecho da resposta
+func (p *ManifestStreamParser) Write(chunk []byte) (int, error) {
+	p.buf = append(p.buf, chunk...)
+	p.scan()
+	return len(chunk), nil
+}
+
+// Directories retorna a lista de diretórios, caso ela já tenha sido recebida por completo
+func (p *ManifestStreamParser) Directories() []string {
+	return p.directories
+}
+
+// PendingFile retorna o nome do arquivo cujo conteúdo começou a ser recebido mas
+// ainda não foi fechado, ou "" se não houver nenhum
+func (p *ManifestStreamParser) PendingFile() string {
+	if p.fileStart < 0 {
+		return ""
+	}
+	return p.fileName
+}
+
+// Feed alimenta o parser com mais um trecho de texto da resposta
+func (p *ManifestStreamParser) Feed(chunk string) {
+	p.Write([]byte(chunk))
+}
+
+// scan avança sobre os bytes ainda não processados do buffer
+func (p *ManifestStreamParser) scan() {
+	for ; p.pos < len(p.buf); p.pos++ {
+		c := p.buf[p.pos]
+
+		// Ignora qualquer texto (como blocos markdown) antes do objeto raiz
+		if !p.started {
+			if c == '{' {
+				p.started = true
+			} else {
+				continue
+			}
+		}
+
+		if p.inString {
+			switch {
+			case p.escaped:
+				p.escaped = false
+			case c == '\\':
+				p.escaped = true
+			case c == '"':
+				p.inString = false
+				p.endString()
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			p.inString = true
+			p.strStart = p.pos
+			top := p.top()
+			p.strIsKey = top != nil && top.object && top.expectKey
+			if !p.strIsKey {
+				p.beginValue()
+			}
+		case '{', '[':
+			p.beginValue()
+			var key string
+			if top := p.top(); top != nil && top.object {
+				key = top.curKey
+			}
+			p.stack = append(p.stack, streamFrame{object: c == '{', start: p.pos, key: key, expectKey: c == '{'})
+		case '}', ']':
+			if len(p.stack) > 0 {
+				closed := p.stack[len(p.stack)-1]
+				p.stack = p.stack[:len(p.stack)-1]
+				if !closed.object && closed.key == "directories" && p.inStructureObject() {
+					json.Unmarshal(p.buf[closed.start:p.pos+1], &p.directories)
+				}
+			}
+			if p.fileStart >= 0 && len(p.stack) == p.fileDepth {
+				p.emitFile(p.pos + 1)
+			}
+		case ':':
+			if top := p.top(); top != nil && top.object {
+				top.expectKey = false
+			}
+		case ',':
+			if top := p.top(); top != nil && top.object {
+				top.expectKey = true
+			}
+		}
+	}
+}
+
+// top retorna o container aberto mais interno
+func (p *ManifestStreamParser) top() *streamFrame {
+	if len(p.stack) == 0 {
+		return nil
+	}
+	return &p.stack[len(p.stack)-1]
+}
+
+// endString trata o fechamento de uma string, seja chave ou valor
+func (p *ManifestStreamParser) endString() {
+	top := p.top()
+	if p.strIsKey {
+		var key string
+		if err := json.Unmarshal(p.buf[p.strStart:p.pos+1], &key); err == nil && top != nil {
+			top.curKey = key
+		}
+		return
+	}
+	if p.fileStart >= 0 && len(p.stack) == p.fileDepth {
+		p.emitFile(p.pos + 1)
+	}
+}
+
+// beginValue marca o início do valor de um arquivo quando estamos dentro de "files"
+func (p *ManifestStreamParser) beginValue() {
+	if p.fileStart >= 0 || !p.inFilesObject() {
+		return
+	}
+	top := p.top()
+	if top.expectKey {
+		return
+	}
+	p.fileName = top.curKey
+	p.fileStart = p.pos
+	p.fileDepth = len(p.stack)
+}
+
+// inStructureObject indica se o container atual é o objeto que contém "directories" e "files"
+func (p *ManifestStreamParser) inStructureObject() bool {
+	switch len(p.stack) {
+	case 1:
+		return true
+	case 2:
+		return p.stack[1].object && p.stack[1].key == "structure"
+	}
+	return false
+}
+
+// inFilesObject indica se o container atual é o objeto "files" do manifesto
+func (p *ManifestStreamParser) inFilesObject() bool {
+	switch len(p.stack) {
+	case 2:
+		return p.stack[1].object && p.stack[1].key == "files"
+	case 3:
+		return p.stack[1].key == "structure" && p.stack[2].object && p.stack[2].key == "files"
+	}
+	return false
+}
+
+// emitFile decodifica o valor completo de um arquivo e o anuncia
+func (p *ManifestStreamParser) emitFile(end int) {
+	raw := p.buf[p.fileStart:end]
+	name := p.fileName
+	p.fileStart = -1
+
+	if !json.Valid(raw) {
+		return
+	}
+	// O valor é copiado do buffer, que continua crescendo; objetos JSON ficam como
+	// json.RawMessage para manter a ordem das chaves
+	value := orderedValue(append(json.RawMessage(nil), raw...))
+	if _, typed := typedEntry(value); !typed {
+		if obj, ok := orderedFields(value); ok {
+			if content, exists := obj["content"]; exists {
+				value = content
+			}
+		}
+	}
+
+	file := StreamedFile{Path: name, Content: value}
+	p.files = append(p.files, file)
+	if p.OnFile != nil {
+		p.OnFile(file)
+	}
+}
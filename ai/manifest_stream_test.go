@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// streamedContents resume os arquivos anunciados pelo parser: strings como estão e
+// objetos JSON compactados
+func streamedContents(t *testing.T, files []StreamedFile) map[string]string {
+	t.Helper()
+	contents := make(map[string]string)
+	for _, file := range files {
+		switch v := file.Content.(type) {
+		case string:
+			contents[file.Path] = v
+		case json.RawMessage:
+			var compact map[string]interface{}
+			if err := json.Unmarshal(v, &compact); err != nil {
+				t.Fatalf("%s: conteúdo inválido: %v", file.Path, err)
+			}
+			data, _ := json.Marshal(compact)
+			contents[file.Path] = string(data)
+		default:
+			t.Fatalf("%s: tipo inesperado %T", file.Path, file.Content)
+		}
+	}
+	return contents
+}
+
+func TestManifestStreamParser(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		files       map[string]string
+		directories []string
+		pending     string
+	}{
+		{
+			name:        "manifesto completo",
+			input:       `{"structure":{"directories":["src"],"files":{"main.go":"package main\n","README.md":"# x"}}}`,
+			files:       map[string]string{"main.go": "package main\n", "README.md": "# x"},
+			directories: []string{"src"},
+		},
+		{
+			name:  "bloco markdown antes do objeto",
+			input: "```json\n{\"structure\":{\"files\":{\"a.txt\":\"a\"}}}\n```",
+			files: map[string]string{"a.txt": "a"},
+		},
+		{
+			name:  "sem o objeto structure",
+			input: `{"directories":[],"files":{"a.txt":"a"}}`,
+			files: map[string]string{"a.txt": "a"},
+		},
+		{
+			name:  "aspas, chaves e barras escapadas",
+			input: `{"structure":{"files":{"a.js":"const s = \"{ } [ ]\\\\\";\n","b.txt":"fim"}}}`,
+			files: map[string]string{"a.js": "const s = \"{ } [ ]\\\\\";\n", "b.txt": "fim"},
+		},
+		{
+			name:  "objeto JSON como conteúdo",
+			input: `{"structure":{"files":{"package.json":{"name":"x","scripts":{"start":"node ."}}}}}`,
+			files: map[string]string{"package.json": `{"name":"x","scripts":{"start":"node ."}}`},
+		},
+		{
+			name:  "objeto com content",
+			input: `{"structure":{"files":{"a.txt":{"content":"texto"}}}}`,
+			files: map[string]string{"a.txt": "texto"},
+		},
+		{
+			name:        "arquivo interrompido",
+			input:       `{"structure":{"directories":["src"],"files":{"a.txt":"a","src/b.go":"package b`,
+			files:       map[string]string{"a.txt": "a"},
+			directories: []string{"src"},
+			pending:     "src/b.go",
+		},
+		{
+			name:  "chaves fora de files são ignoradas",
+			input: `{"structure":{"meta":{"a.txt":"não é arquivo"},"files":{"b.txt":"b"}}}`,
+			files: map[string]string{"b.txt": "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// O resultado não pode depender de como a resposta foi dividida em trechos
+			for size := 1; size <= len(tt.input); size++ {
+				var announced []StreamedFile
+				parser := NewManifestStreamParser(func(file StreamedFile) {
+					announced = append(announced, file)
+				})
+				for start := 0; start < len(tt.input); start += size {
+					end := start + size
+					if end > len(tt.input) {
+						end = len(tt.input)
+					}
+					parser.Feed(tt.input[start:end])
+				}
+
+				if got := streamedContents(t, parser.Files()); !reflect.DeepEqual(got, tt.files) {
+					t.Fatalf("trechos de %d: arquivos = %q, esperado %q", size, got, tt.files)
+				}
+				if len(announced) != len(parser.Files()) {
+					t.Fatalf("trechos de %d: %d arquivos anunciados, %d encontrados", size, len(announced), len(parser.Files()))
+				}
+				if !reflect.DeepEqual(parser.Directories(), tt.directories) && (len(tt.directories) > 0 || len(parser.Directories()) > 0) {
+					t.Fatalf("trechos de %d: diretórios = %q, esperado %q", size, parser.Directories(), tt.directories)
+				}
+				if got := parser.PendingFile(); got != tt.pending {
+					t.Fatalf("trechos de %d: arquivo pendente = %q, esperado %q", size, got, tt.pending)
+				}
+			}
+		})
+	}
+}
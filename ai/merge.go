@@ -0,0 +1,216 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Marcadores de conflito escritos nos arquivos alterados dos dois lados
+const (
+	conflictStart  = "<<<<<<< local"
+	conflictMiddle = "======="
+	conflictEnd    = ">>>>>>> zion"
+)
+
+// matchLines associa cada linha de base à linha igual de other na maior subsequência
+// comum, ou -1 quando a linha não foi mantida
+func matchLines(base, other []string) []int {
+	matches := make([]int, len(base))
+	i, j := 0, 0
+	for _, op := range diffLines(base, other) {
+		switch op.Kind {
+		case ' ':
+			matches[i] = j
+			i++
+			j++
+		case '-':
+			matches[i] = -1
+			i++
+		case '+':
+			j++
+		}
+	}
+	return matches
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge3 faz o merge de três vias, por linhas, das alterações de ours e theirs sobre base.
+// Trechos alterados dos dois lados de formas diferentes ficam entre marcadores de conflito
+// (local é ours, zion é theirs). Retorna o texto e o número de conflitos.
+func Merge3(base, ours, theirs string) (string, int) {
+	switch {
+	case ours == theirs || theirs == base:
+		return ours, 0
+	case ours == base:
+		return theirs, 0
+	}
+
+	o, a, b := splitLines(base), splitLines(ours), splitLines(theirs)
+	ma, mb := matchLines(o, a), matchLines(o, b)
+
+	var out []string
+	conflicts := 0
+	resolve := func(oc, ac, bc []string) {
+		switch {
+		case equalLines(ac, oc):
+			out = append(out, bc...)
+		case equalLines(bc, oc), equalLines(ac, bc):
+			out = append(out, ac...)
+		default:
+			conflicts++
+			out = append(out, conflictStart)
+			out = append(out, ac...)
+			out = append(out, conflictMiddle)
+			out = append(out, bc...)
+			out = append(out, conflictEnd)
+		}
+	}
+
+	// Percorre base alternando trechos estáveis (mantidos nos dois lados) e trechos
+	// instáveis, que vão até a próxima linha de base mantida nos dois lados
+	i, ia, ib := 0, 0, 0
+	for i < len(o) {
+		if ma[i] == ia && mb[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+			continue
+		}
+		j := i
+		for j < len(o) && (ma[j] < 0 || mb[j] < 0) {
+			j++
+		}
+		if j == len(o) {
+			break
+		}
+		resolve(o[i:j], a[ia:ma[j]], b[ib:mb[j]])
+		i, ia, ib = j, ma[j], mb[j]
+	}
+	if i < len(o) || ia < len(a) || ib < len(b) {
+		resolve(o[i:], a[ia:], b[ib:])
+	}
+
+	// A quebra de linha final segue a mesma regra das linhas
+	newline := strings.HasSuffix(ours, "\n")
+	if newline == strings.HasSuffix(base, "\n") {
+		newline = strings.HasSuffix(theirs, "\n")
+	}
+	text := strings.Join(out, "\n")
+	if newline && len(out) > 0 {
+		text += "\n"
+	}
+	return text, conflicts
+}
+
+// Conflitos que o merge não resolve com marcadores; nesses casos o arquivo local é mantido
+const (
+	// NoteRemovedLocally: removido localmente e alterado na nova geração
+	NoteRemovedLocally = "removed_locally"
+	// NoteRemovedUpstream: alterado localmente e removido na nova geração
+	NoteRemovedUpstream = "removed_upstream"
+	// NoteBinary: arquivo binário alterado localmente e na nova geração
+	NoteBinary = "binary"
+)
+
+// MergedFile é o resultado do merge de três vias de um arquivo do projeto. Conflict
+// indica trechos com marcadores de conflito ou, em Note, um conflito sem marcadores
+// (NoteRemovedLocally, NoteRemovedUpstream ou NoteBinary), caso em que o arquivo local
+// é mantido.
+type MergedFile struct {
+	FileChange
+	Conflict bool
+	Note     string
+}
+
+// MergeProject compara o conteúdo gerado originalmente (base), os arquivos atuais do
+// projeto em dir e a nova geração (next). Alterações feitas só de um lado são aplicadas,
+// arquivos criados apenas pelo usuário são mantidos e arquivos alterados dos dois lados
+// passam pelo merge de três vias.
+func MergeProject(dir string, base, next *ProjectManifest) ([]MergedFile, error) {
+	baseFiles, nextFiles := base.FileMap(), next.FileMap()
+	paths := make(map[string]bool)
+	for path := range baseFiles {
+		paths[path] = true
+	}
+	for path := range nextFiles {
+		paths[path] = true
+	}
+
+	var merged []MergedFile
+	for path := range paths {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("erro ao ler %s: %v", path, err)
+		}
+		ours, hasOurs := string(data), err == nil
+		baseText, hasBase := baseFiles[path]
+		theirs, hasTheirs := nextFiles[path]
+
+		change := FileChange{Path: path, OldContent: ours, NewContent: theirs}
+		switch {
+		case hasOurs == hasTheirs && ours == theirs:
+			continue
+		case hasBase && hasOurs && ours == baseText:
+			// O usuário não alterou o arquivo: vale a nova geração
+			change.Kind = ChangeModified
+			if !hasTheirs {
+				change.Kind = ChangeRemoved
+			}
+			merged = append(merged, MergedFile{FileChange: change})
+		case hasBase && hasTheirs && theirs == baseText:
+			// A nova geração não mudou o arquivo: vale a versão local
+			continue
+		case hasBase && !hasOurs:
+			merged = append(merged, MergedFile{FileChange: FileChange{Path: path, Kind: ChangeRemoved}, Conflict: true,
+				Note: NoteRemovedLocally})
+		case hasBase && !hasTheirs:
+			merged = append(merged, MergedFile{FileChange: FileChange{Path: path, Kind: ChangeRemoved, OldContent: ours}, Conflict: true,
+				Note: NoteRemovedUpstream})
+		case !hasOurs:
+			change.Kind = ChangeAdded
+			merged = append(merged, MergedFile{FileChange: change})
+		case !utf8.ValidString(ours) || !utf8.ValidString(theirs) || !utf8.ValidString(baseText):
+			change.Kind = ChangeModified
+			merged = append(merged, MergedFile{FileChange: change, Conflict: true,
+				Note: NoteBinary})
+		default:
+			text, conflicts := Merge3(baseText, ours, theirs)
+			if text == ours {
+				continue
+			}
+			change.Kind = ChangeModified
+			change.NewContent = text
+			merged = append(merged, MergedFile{FileChange: change, Conflict: conflicts > 0})
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Path < merged[j].Path })
+	return merged, nil
+}
+
+// MergeChanges retorna as alterações a gravar no projeto. Remoções e arquivos binários em
+// conflito não são aplicados: o arquivo local é mantido.
+func MergeChanges(merged []MergedFile) []FileChange {
+	var changes []FileChange
+	for _, file := range merged {
+		if file.Conflict && file.Note != "" {
+			continue
+		}
+		changes = append(changes, file.FileChange)
+	}
+	return changes
+}
@@ -0,0 +1,189 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{
+			name: "sem alterações",
+			base: "a\nb\n", ours: "a\nb\n", theirs: "a\nb\n",
+			want: "a\nb\n",
+		},
+		{
+			name: "só local",
+			base: "a\nb\n", ours: "a\nB\n", theirs: "a\nb\n",
+			want: "a\nB\n",
+		},
+		{
+			name: "só na nova geração",
+			base: "a\nb\n", ours: "a\nb\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "mesma alteração dos dois lados",
+			base: "a\nb\n", ours: "a\nx\n", theirs: "a\nx\n",
+			want: "a\nx\n",
+		},
+		{
+			name: "alterações em trechos diferentes",
+			base: "a\nb\nc\nd\ne\n", ours: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n",
+			want: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "linhas adicionadas nas duas pontas",
+			base: "b\n", ours: "a\nb\n", theirs: "b\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "remoção local e alteração em outro trecho",
+			base: "a\nb\nc\nd\n", ours: "a\nc\nd\n", theirs: "a\nb\nc\nD\n",
+			want: "a\nc\nD\n",
+		},
+		{
+			// Como no git, alterações em linhas vizinhas são um conflito
+			name: "remoção local e alteração na linha seguinte",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nb\nC\n",
+			want:      "a\n" + conflictStart + "\nc\n" + conflictMiddle + "\nb\nC\n" + conflictEnd + "\n",
+			conflicts: 1,
+		},
+		{
+			name: "conflito no mesmo trecho",
+			base: "a\nb\nc\n", ours: "a\nlocal\nc\n", theirs: "a\nzion\nc\n",
+			want:      "a\n" + conflictStart + "\nlocal\n" + conflictMiddle + "\nzion\n" + conflictEnd + "\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "conflito no fim do arquivo",
+			base: "a\n", ours: "a\nlocal\n", theirs: "a\nzion\n",
+			want:      "a\n" + conflictStart + "\nlocal\n" + conflictMiddle + "\nzion\n" + conflictEnd + "\n",
+			conflicts: 1,
+		},
+		{
+			name: "dois conflitos",
+			base: "a\nb\nc\nd\ne\n", ours: "1\nb\nc\nd\n5\n", theirs: "x\nb\nc\nd\ny\n",
+			want: conflictStart + "\n1\n" + conflictMiddle + "\nx\n" + conflictEnd + "\nb\nc\nd\n" +
+				conflictStart + "\n5\n" + conflictMiddle + "\ny\n" + conflictEnd + "\n",
+			conflicts: 2,
+		},
+		{
+			name: "quebra de linha final removida localmente",
+			base: "a\nb\n", ours: "a\nb", theirs: "a\nb\nc\n",
+			want: "a\nb\nc",
+		},
+		{
+			name: "quebra de linha final adicionada na nova geração",
+			base: "a\nb", ours: "A\nb", theirs: "a\nb\n",
+			want: "A\nb\n",
+		},
+		{
+			name: "base vazia",
+			base: "", ours: "local\n", theirs: "zion\n",
+			want:      conflictStart + "\nlocal\n" + conflictMiddle + "\nzion\n" + conflictEnd + "\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3(tt.base, tt.ours, tt.theirs)
+			if got != tt.want {
+				t.Errorf("Merge3 = %q, esperado %q", got, tt.want)
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("conflitos = %d, esperado %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestMergeProject(t *testing.T) {
+	dir := t.TempDir()
+	local := map[string]string{
+		"igual.txt":          "igual\n",
+		"sem_alteracao.txt":  "base\n",
+		"so_local.txt":       "local\n",
+		"merge.txt":          "A\nb\nc\n",
+		"conflito.txt":       "local\n",
+		"removido_zion.txt":  "local\n",
+		"inalterado_rm.txt":  "base\n",
+		"do_usuario.txt":     "meu\n",
+		"binario.bin":        "\xff\x01local",
+		"existente_novo.txt": "existente\n",
+	}
+	for path, content := range local {
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := NewProjectManifest("p", nil, map[string]string{
+		"igual.txt":          "igual\n",
+		"sem_alteracao.txt":  "base\n",
+		"so_local.txt":       "base\n",
+		"merge.txt":          "a\nb\nc\n",
+		"conflito.txt":       "base\n",
+		"removido_local.txt": "base\n",
+		"removido_zion.txt":  "base\n",
+		"inalterado_rm.txt":  "base\n",
+		"binario.bin":        "\xff\x01base",
+	})
+	next := NewProjectManifest("p", nil, map[string]string{
+		"igual.txt":          "igual\n",
+		"sem_alteracao.txt":  "novo\n",
+		"so_local.txt":       "base\n",
+		"merge.txt":          "a\nb\nC\n",
+		"conflito.txt":       "zion\n",
+		"removido_local.txt": "novo\n",
+		"binario.bin":        "\xff\x01zion",
+		"novo.txt":           "novo\n",
+		"existente_novo.txt": "zion\n",
+	})
+
+	merged, err := MergeProject(dir, base, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type result struct {
+		kind     string
+		conflict bool
+		note     string
+		content  string
+	}
+	got := make(map[string]result)
+	for _, file := range merged {
+		got[file.Path] = result{file.Kind, file.Conflict, file.Note, file.NewContent}
+	}
+	want := map[string]result{
+		"sem_alteracao.txt":  {ChangeModified, false, "", "novo\n"},
+		"merge.txt":          {ChangeModified, false, "", "A\nb\nC\n"},
+		"conflito.txt":       {ChangeModified, true, "", conflictStart + "\nlocal\n" + conflictMiddle + "\nzion\n" + conflictEnd + "\n"},
+		"removido_local.txt": {ChangeRemoved, true, NoteRemovedLocally, ""},
+		"removido_zion.txt":  {ChangeRemoved, true, NoteRemovedUpstream, ""},
+		"inalterado_rm.txt":  {ChangeRemoved, false, "", ""},
+		"binario.bin":        {ChangeModified, true, NoteBinary, "\xff\x01zion"},
+		"novo.txt":           {ChangeAdded, false, "", "novo\n"},
+		"existente_novo.txt": {ChangeModified, true, "", conflictStart + "\nexistente\n" + conflictMiddle + "\nzion\n" + conflictEnd + "\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeProject:\n%+v\nesperado:\n%+v", got, want)
+	}
+
+	// Conflitos sem marcadores mantêm o arquivo local
+	var applied []string
+	for _, change := range MergeChanges(merged) {
+		applied = append(applied, change.Path)
+	}
+	wantApplied := []string{"conflito.txt", "existente_novo.txt", "inalterado_rm.txt", "merge.txt", "novo.txt", "sem_alteracao.txt"}
+	if !reflect.DeepEqual(applied, wantApplied) {
+		t.Errorf("MergeChanges = %q, esperado %q", applied, wantApplied)
+	}
+}
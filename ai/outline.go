@@ -0,0 +1,269 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"zion/i18n"
+	"zion/plugins"
+	"zion/prompts"
+)
+
+// OutlineFile descreve um arquivo planejado e o seu propósito
+type OutlineFile struct {
+	Path    string `json:"path"`
+	Purpose string `json:"purpose"`
+}
+
+// ProjectOutline é a árvore do projeto gerada na primeira etapa do modo multi-etapas
+type ProjectOutline struct {
+	Directories []string      `json:"directories"`
+	Files       []OutlineFile `json:"files"`
+}
+
+// DefaultOutlineWorkers é o número padrão de requisições simultâneas na geração dos arquivos
+const DefaultOutlineWorkers = 4
+
+// GenerateProjectOutline pede ao modelo apenas a árvore do projeto, com uma linha
+// de propósito por arquivo, sem o conteúdo dos arquivos
+func GenerateProjectOutline(language, framework, projectName, description string) (*ProjectOutline, error) {
+	ctx := &plugins.ScaffoldContext{
+		ProjectName: projectName,
+		Language:    language,
+		Description: description,
+	}
+
+	// Executar o hook BeforeGeneration para todos os plugins
+	ctx = plugins.ExecuteHook(plugins.BeforeGeneration, ctx)
+
+	projectDesc, err := buildProjectDescription(language, framework, projectName, description)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := renderPrompt("outline.tmpl", prompts.Data{ProjectDescription: projectDesc})
+	if err != nil {
+		return nil, err
+	}
+
+	// Os plugins também podem influenciar a árvore planejada
+	ctx.Prompt = prompt
+	ctx = plugins.ExecuteHook(plugins.ModifyPrompt, ctx)
+
+	response, err := callGeminiAPI(ctx.Prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var outline ProjectOutline
+	if err := json.Unmarshal([]byte(response), &outline); err != nil {
+		return nil, fmt.Errorf("erro ao processar a árvore do projeto: %v", err)
+	}
+	if len(outline.Files) == 0 {
+		return nil, fmt.Errorf("a árvore gerada não contém arquivos")
+	}
+
+	return &outline, nil
+}
+
+// LoadProjectOutline lê uma árvore salva anteriormente (possivelmente editada pelo usuário)
+func LoadProjectOutline(path string) (*ProjectOutline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler árvore do projeto: %v", err)
+	}
+
+	var outline ProjectOutline
+	if err := json.Unmarshal(data, &outline); err != nil {
+		return nil, fmt.Errorf("erro ao processar árvore do projeto: %v", err)
+	}
+	return &outline, nil
+}
+
+// SaveProjectOutline grava a árvore em disco para que o usuário possa revisá-la e editá-la
+func SaveProjectOutline(path string, outline *ProjectOutline) error {
+	data, err := json.MarshalIndent(outline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar árvore do projeto: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar árvore do projeto: %v", err)
+	}
+	return nil
+}
+
+// outlineFileResult guarda o resultado da geração de um único arquivo
+type outlineFileResult struct {
+	content interface{}
+	err     error
+}
+
+// GenerateFilesFromOutline gera o conteúdo de cada arquivo da árvore em requisições
+// separadas e simultâneas, limitadas a workers, e remonta tudo no mesmo formato de
+// ScaffoldResponse usado pela geração em uma etapa. onFile é chamado ao fim de cada arquivo.
+// Os arquivos que falharem ficam de fora da resposta e são listados ao final; só é
+// retornado erro se nenhum arquivo for gerado.
+func GenerateFilesFromOutline(language, framework, projectName, description string, outline *ProjectOutline, workers int, onFile func(path string, err error)) (string, error) {
+	projectDesc, err := buildProjectDescription(language, framework, projectName, description)
+	if err != nil {
+		return "", err
+	}
+	hookCtx := plugins.ScaffoldContext{
+		ProjectName: projectName,
+		Language:    language,
+		Description: description,
+	}
+	contents, failed, err := generateOutlineFiles(hookCtx, projectDesc, FormatOutline(outline), outline.Files, workers, onFile)
+	if err != nil {
+		return "", err
+	}
+	if len(contents) == 0 {
+		return "", fmt.Errorf("falha ao gerar %d arquivo(s):\n%s", len(failed), strings.Join(failed, "\n"))
+	}
+	reportOutlineFailures(failed)
+
+	var scaffoldResp ScaffoldResponse
+	scaffoldResp.Structure.Directories = outline.Directories
+	scaffoldResp.Structure.Files = make(map[string]interface{})
+	for path, content := range contents {
+		scaffoldResp.Structure.Files[path] = map[string]interface{}{"content": content}
+	}
+
+	data, err := json.Marshal(scaffoldResp)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar JSON final: %v", err)
+	}
+
+	ctx := &hookCtx
+	ctx.Response = string(data)
+
+	// Executar o hook AfterGeneration para todos os plugins
+	ctx = plugins.ExecuteHook(plugins.AfterGeneration, ctx)
+
+	processedResponse, err := processScaffoldResponse(ctx.Response)
+	if err != nil {
+		return "", fmt.Errorf("erro ao processar resposta: %v", err)
+	}
+	return processedResponse, nil
+}
+
+// generateOutlineFiles gera o conteúdo dos arquivos informados usando no máximo workers
+// requisições simultâneas; tree é a árvore completa enviada como contexto em cada prompt.
+// Os prompts passam pelo hook ModifyPrompt dos plugins com os dados de hookCtx. Retorna
+// o conteúdo dos arquivos gerados e, ordenadas, as falhas dos demais; o erro indica que
+// nenhuma requisição pôde ser feita.
+func generateOutlineFiles(hookCtx plugins.ScaffoldContext, projectDesc, tree string, files []OutlineFile, workers int, onFile func(path string, err error)) (map[string]interface{}, []string, error) {
+	if workers < 1 {
+		workers = DefaultOutlineWorkers
+	}
+
+	// Os hooks rodam aqui, um arquivo por vez, pois os plugins não são chamados de
+	// várias goroutines nos demais fluxos
+	set, err := prompts.Load(".")
+	if err != nil {
+		return nil, nil, err
+	}
+	filePrompts := make([]string, len(files))
+	for i, file := range files {
+		prompt, err := set.Render("outline_file.tmpl", prompts.Data{
+			ProjectDescription: projectDesc,
+			Tree:               tree,
+			FilePath:           file.Path,
+			FilePurpose:        file.Purpose,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx := hookCtx
+		ctx.Prompt = prompt
+		filePrompts[i] = plugins.ExecuteHook(plugins.ModifyPrompt, &ctx).Prompt
+	}
+
+	results := make([]outlineFileResult, len(files))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				file := files[i]
+				content, err := generateOutlineFile(filePrompts[i])
+				results[i] = outlineFileResult{content: content, err: err}
+				if onFile != nil {
+					mu.Lock()
+					onFile(file.Path, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	contents := make(map[string]interface{})
+	var failed []string
+	for i, result := range results {
+		if result.err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", files[i].Path, result.err))
+			continue
+		}
+		contents[files[i].Path] = result.content
+	}
+	sort.Strings(failed)
+	return contents, failed, nil
+}
+
+// reportOutlineFailures lista os arquivos que não puderam ser gerados
+func reportOutlineFailures(failed []string) {
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Print(i18n.T("ai.outline.failed", len(failed)))
+	for _, failure := range failed {
+		fmt.Printf("   ❌ %s\n", failure)
+	}
+	fmt.Print(i18n.T("ai.outline.failed_hint"))
+}
+
+// generateOutlineFile pede ao modelo o conteúdo de um único arquivo da árvore
+func generateOutlineFile(prompt string) (interface{}, error) {
+	result, err := sendGeminiRequest(buildGeminiRequest(prompt))
+	if err != nil {
+		return nil, err
+	}
+	if result, err = continueTruncatedResponse(userContents(prompt), result, nil); err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(extractJSONContent(result.Text))
+	var fileResp struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(text), &fileResp); err != nil || fileResp.Content == nil || string(fileResp.Content) == "null" {
+		// O modelo às vezes devolve o conteúdo puro em vez do JSON pedido
+		return text, nil
+	}
+	return orderedValue(fileResp.Content), nil
+}
+
+// FormatOutline renderiza a árvore como uma lista legível, usada nos prompts e na revisão
+func FormatOutline(outline *ProjectOutline) string {
+	var b strings.Builder
+	for _, dir := range outline.Directories {
+		fmt.Fprintf(&b, "- %s/\n", strings.TrimSuffix(dir, "/"))
+	}
+	for _, file := range outline.Files {
+		fmt.Fprintf(&b, "- %s: %s\n", file.Path, file.Purpose)
+	}
+	return b.String()
+}
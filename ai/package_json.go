@@ -0,0 +1,397 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// packageFieldOrder é a ordem convencional dos campos de um package.json. Campos fora
+// desta lista vêm depois, na ordem em que aparecem.
+var packageFieldOrder = []string{
+	"name", "version", "private", "description", "keywords", "homepage", "bugs", "repository",
+	"funding", "license", "author", "contributors", "type", "main", "module", "browser", "types",
+	"typings", "bin", "exports", "files", "workspaces", "scripts", "dependencies", "devDependencies",
+	"peerDependencies", "peerDependenciesMeta", "optionalDependencies", "bundledDependencies",
+	"overrides", "engines", "os", "cpu", "publishConfig",
+}
+
+// PackageDependencyFields são os campos de um package.json que mapeiam pacotes em faixas de versão
+var PackageDependencyFields = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// PackageJSON é um package.json que preserva a ordem e o conteúdo original de todos os campos,
+// inclusive dos que o Zion não conhece
+type PackageJSON struct {
+	fields []packageField
+}
+
+type packageField struct {
+	key   string
+	value json.RawMessage
+}
+
+// PackageDependency é uma dependência de um package.json
+type PackageDependency struct {
+	Name  string
+	Range string
+}
+
+// ParsePackageJSON decodifica um package.json mantendo a ordem dos campos
+func ParsePackageJSON(data []byte) (*PackageJSON, error) {
+	fields, err := decodeOrderedObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("package.json inválido: %v", err)
+	}
+	return &PackageJSON{fields: fields}, nil
+}
+
+// decodeOrderedObject decodifica um objeto JSON em campos na ordem original, sem
+// interpretar os valores. Chaves repetidas ficam com o último valor, como em encoding/json.
+func decodeOrderedObject(data []byte) ([]packageField, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, fmt.Errorf("esperado um objeto JSON")
+	}
+
+	var fields []packageField
+	index := make(map[string]int)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		if i, ok := index[key]; ok {
+			fields[i].value = value
+			continue
+		}
+		index[key] = len(fields)
+		fields = append(fields, packageField{key: key, value: value})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("conteúdo após o fim do objeto")
+	}
+	return fields, nil
+}
+
+// encodeOrderedObject serializa os campos na ordem informada
+func encodeOrderedObject(fields []packageField) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := marshalJSONValue(field.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(field.value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSONValue serializa um valor sem escapar <, > e &, que são comuns em faixas de versão
+func marshalJSONValue(value interface{}) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Field retorna o valor bruto de um campo
+func (p *PackageJSON) Field(key string) (json.RawMessage, bool) {
+	for _, field := range p.fields {
+		if field.key == key {
+			return field.value, true
+		}
+	}
+	return nil, false
+}
+
+// SetField altera um campo mantendo sua posição, ou o acrescenta no fim
+func (p *PackageJSON) SetField(key string, value interface{}) error {
+	raw, err := marshalJSONValue(value)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar campo %s: %v", key, err)
+	}
+	for i := range p.fields {
+		if p.fields[i].key == key {
+			p.fields[i].value = raw
+			return nil
+		}
+	}
+	p.fields = append(p.fields, packageField{key: key, value: raw})
+	return nil
+}
+
+// stringField retorna um campo de texto, ou "" se ele não existir ou não for texto
+func (p *PackageJSON) stringField(key string) string {
+	var value string
+	if raw, ok := p.Field(key); ok {
+		json.Unmarshal(raw, &value)
+	}
+	return value
+}
+
+// Name retorna o nome do pacote
+func (p *PackageJSON) Name() string {
+	return p.stringField("name")
+}
+
+// Version retorna a versão do pacote
+func (p *PackageJSON) Version() string {
+	return p.stringField("version")
+}
+
+// Dependencies retorna as dependências de um campo (como "devDependencies") na ordem do arquivo
+func (p *PackageJSON) Dependencies(field string) ([]PackageDependency, error) {
+	raw, ok := p.Field(field)
+	if !ok {
+		return nil, nil
+	}
+	entries, err := decodeOrderedObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf(`"%s" deve ser um objeto`, field)
+	}
+	deps := make([]PackageDependency, 0, len(entries))
+	for _, entry := range entries {
+		var spec string
+		if err := json.Unmarshal(entry.value, &spec); err != nil {
+			return nil, fmt.Errorf(`versão da dependência %q em "%s" deve ser um texto`, entry.key, field)
+		}
+		deps = append(deps, PackageDependency{Name: entry.key, Range: spec})
+	}
+	return deps, nil
+}
+
+// SetDependencies substitui as dependências de um campo, na ordem informada
+func (p *PackageJSON) SetDependencies(field string, deps []PackageDependency) error {
+	entries := make([]packageField, 0, len(deps))
+	for _, dep := range deps {
+		value, err := marshalJSONValue(dep.Range)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, packageField{key: dep.Name, value: value})
+	}
+	raw, err := encodeOrderedObject(entries)
+	if err != nil {
+		return err
+	}
+	return p.SetField(field, json.RawMessage(raw))
+}
+
+// SortDependencies ordena alfabeticamente cada campo de dependências, como faz o npm.
+// Campos com valores inválidos são mantidos como estão.
+func (p *PackageJSON) SortDependencies() {
+	for _, field := range PackageDependencyFields {
+		deps, err := p.Dependencies(field)
+		if err != nil || deps == nil {
+			continue
+		}
+		sort.SliceStable(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+		p.SetDependencies(field, deps)
+	}
+}
+
+// CanonicalOrder reordena os campos na ordem convencional do package.json
+func (p *PackageJSON) CanonicalOrder() {
+	rank := make(map[string]int, len(packageFieldOrder))
+	for i, key := range packageFieldOrder {
+		rank[key] = i
+	}
+	sort.SliceStable(p.fields, func(i, j int) bool {
+		ri, okI := rank[p.fields[i].key]
+		rj, okJ := rank[p.fields[j].key]
+		switch {
+		case okI && okJ:
+			return ri < rj
+		case okI != okJ:
+			return okI
+		default:
+			return false
+		}
+	})
+}
+
+// Marshal serializa o package.json com a indentação informada e uma quebra de linha final
+func (p *PackageJSON) Marshal(indent string) ([]byte, error) {
+	compact, err := encodeOrderedObject(p.fields)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact, "", indent); err != nil {
+		return nil, fmt.Errorf("erro ao serializar package.json: %v", err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// Problems valida o nome, a versão e as dependências do pacote
+func (p *PackageJSON) Problems() []string {
+	var problems []string
+	if raw, ok := p.Field("name"); !ok {
+		problems = append(problems, `campo "name" ausente`)
+	} else if name := p.Name(); name == "" {
+		problems = append(problems, fmt.Sprintf(`campo "name" inválido: %s`, raw))
+	} else if err := ValidatePackageName(name); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if raw, ok := p.Field("version"); ok && !exactVersionPattern.MatchString(p.Version()) {
+		problems = append(problems, fmt.Sprintf("versão inválida: %s", raw))
+	}
+
+	for _, field := range PackageDependencyFields {
+		deps, err := p.Dependencies(field)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		for _, dep := range deps {
+			if err := ValidatePackageName(dep.Name); err != nil {
+				problems = append(problems, fmt.Sprintf(`dependência inválida em "%s": %v`, field, err))
+			}
+			if err := ValidateVersionRange(dep.Range); err != nil {
+				problems = append(problems, fmt.Sprintf(`dependência %s em "%s": %v`, dep.Name, field, err))
+			}
+		}
+	}
+	return problems
+}
+
+var (
+	packageNamePattern  = regexp.MustCompile(`^(?:@[a-z0-9-*~][a-z0-9-*._~]*/)?[a-z0-9-~][a-z0-9-._~]*$`)
+	exactVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+	partialVersion      = `v?(?:\d+|[xX*])(?:\.(?:\d+|[xX*])){0,2}(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`
+	comparatorPattern   = regexp.MustCompile(`^(?:[<>]=?|=|~>?|\^)?` + partialVersion + `$`)
+	partialPattern      = regexp.MustCompile(`^` + partialVersion + `$`)
+	operatorPattern     = regexp.MustCompile(`^(?:[<>]=?|=|~>?|\^)$`)
+	distTagPattern      = regexp.MustCompile(`^[A-Za-z][\w.-]*$`)
+	repositoryPattern   = regexp.MustCompile(`^[\w.-]+/[\w.-]+(?:#.+)?$`)
+)
+
+// rangeProtocols são prefixos de especificações que não são faixas semver (caminhos, git, URLs, aliases)
+var rangeProtocols = []string{"file:", "link:", "workspace:", "npm:", "portal:", "patch:", "git:", "git+", "github:", "gitlab:", "bitbucket:", "http://", "https://"}
+
+// ValidatePackageName verifica um nome de pacote npm, com ou sem escopo
+func ValidatePackageName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("nome de pacote vazio")
+	case len(name) > 214:
+		return fmt.Errorf("nome de pacote com mais de 214 caracteres: %q", name)
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return fmt.Errorf("nome de pacote não pode começar com . ou _: %q", name)
+	case !packageNamePattern.MatchString(name):
+		return fmt.Errorf("nome de pacote inválido: %q", name)
+	}
+	return nil
+}
+
+// ValidateVersionRange verifica a especificação de versão de uma dependência: uma faixa
+// semver (^1.2.3, ~1.2, >=1 <2, 1.x, 1.0.0 - 2.0.0, uniões com ||), uma tag como latest
+// ou uma referência a caminho, repositório git, URL ou alias npm:
+func ValidateVersionRange(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return fmt.Errorf("faixa de versão vazia")
+	}
+	for _, protocol := range rangeProtocols {
+		if strings.HasPrefix(spec, protocol) {
+			return nil
+		}
+	}
+	if distTagPattern.MatchString(spec) || repositoryPattern.MatchString(spec) {
+		return nil
+	}
+
+	for _, set := range strings.Split(spec, "||") {
+		set = strings.TrimSpace(set)
+		if set == "" {
+			return fmt.Errorf("faixa de versão inválida: %q", spec)
+		}
+		if bounds := strings.Split(set, " - "); len(bounds) == 2 {
+			if !partialPattern.MatchString(strings.TrimSpace(bounds[0])) || !partialPattern.MatchString(strings.TrimSpace(bounds[1])) {
+				return fmt.Errorf("faixa de versão inválida: %q", spec)
+			}
+			continue
+		}
+		tokens := strings.Fields(set)
+		for i := 0; i < len(tokens); i++ {
+			comparator := tokens[i]
+			// Aceita espaço entre o operador e a versão, como em ">= 1.2.0"
+			if operatorPattern.MatchString(comparator) && i+1 < len(tokens) {
+				i++
+				comparator += tokens[i]
+			}
+			if !comparatorPattern.MatchString(comparator) {
+				return fmt.Errorf("faixa de versão inválida: %q", spec)
+			}
+		}
+	}
+	return nil
+}
+
+// isPackageJSON indica se o caminho é um package.json
+func isPackageJSON(filePath string) bool {
+	return path.Base(filepath.ToSlash(filePath)) == "package.json"
+}
+
+// detectIndent retorna a indentação da primeira linha indentada de um texto JSON
+func detectIndent(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+// formatPackageJSON normaliza um package.json pelo modelo tipado: as dependências são
+// ordenadas e a ordem e a indentação dos campos são mantidas. Campos desconhecidos são mantidos.
+func formatPackageJSON(data []byte) (string, error) {
+	pkg, err := ParsePackageJSON(data)
+	if err != nil {
+		return "", err
+	}
+	pkg.SortDependencies()
+	out, err := pkg.Marshal(detectIndent(string(data)))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// normalizePackageJSON passa um package.json recebido como texto pelo modelo tipado,
+// mantendo a ordem e a indentação dos campos. Conteúdo inválido é mantido como está.
+func normalizePackageJSON(content string) string {
+	formatted, err := formatPackageJSON([]byte(content))
+	if err != nil {
+		return content
+	}
+	return formatted
+}
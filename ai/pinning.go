@@ -0,0 +1,514 @@
+package ai
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"zion/config"
+
+	"golang.org/x/mod/modfile"
+)
+
+// PinnedDependency é o resultado da fixação da versão de uma dependência
+type PinnedDependency struct {
+	Manifest  string
+	Ecosystem string
+	Name      string
+	From      string
+	To        string
+	// Widened indica que nenhuma versão publicada satisfazia a especificação original e
+	// foi escolhida a mais recente com a mesma versão major
+	Widened bool
+	// Problem explica por que a dependência, ou o manifesto inteiro quando Name é vazio,
+	// não pôde ser resolvida
+	Problem string
+}
+
+// Changed indica se a especificação da dependência foi reescrita
+func (d PinnedDependency) Changed() bool {
+	return d.Problem == "" && d.To != d.From
+}
+
+// PinReport reúne o resultado da fixação de versões de um projeto
+type PinReport struct {
+	Dependencies []PinnedDependency
+	// Manifests são os manifestos alterados
+	Manifests []string
+}
+
+// Changed retorna as dependências reescritas
+func (r *PinReport) Changed() []PinnedDependency {
+	var changed []PinnedDependency
+	for _, dep := range r.Dependencies {
+		if dep.Changed() {
+			changed = append(changed, dep)
+		}
+	}
+	return changed
+}
+
+// Problems retorna as dependências que não puderam ser resolvidas
+func (r *PinReport) Problems() []PinnedDependency {
+	var problems []PinnedDependency
+	for _, dep := range r.Dependencies {
+		if dep.Problem != "" {
+			problems = append(problems, dep)
+		}
+	}
+	return problems
+}
+
+// manifestPinner reescreve as dependências de um manifesto, retornando o novo conteúdo
+type manifestPinner func(p *dependencyPinner, content string) (string, error)
+
+// manifestPinnerFor retorna o tratamento do manifesto, ou nil se o arquivo não for um
+func manifestPinnerFor(rel string) manifestPinner {
+	base := path.Base(rel)
+	switch {
+	case base == "package.json":
+		return pinPackageJSON
+	case base == "go.mod":
+		return pinGoMod
+	case base == "pyproject.toml":
+		return pinPyproject
+	case base == "Cargo.toml":
+		return pinCargo
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return pinRequirements
+	}
+	return nil
+}
+
+// PinDependencies resolve cada dependência dos manifestos do projeto em dir (package.json,
+// go.mod, requirements*.txt, pyproject.toml e Cargo.toml) no registro do ecossistema e
+// reescreve a especificação para a versão publicada mais recente compatível com ela.
+// Quando nenhuma versão publicada satisfaz a especificação, é usada a mais recente com a
+// mesma versão major. Os registros vêm de config.yaml, com registries tendo precedência.
+// Com dryRun, nenhum arquivo é alterado.
+func PinDependencies(dir string, registries map[string]string, dryRun bool) (*PinReport, error) {
+	cfg := config.LoadConfig()
+	if cfg.Err != nil {
+		// Sem o config.yaml, os registros configurados nele ficariam de fora sem aviso
+		return nil, cfg.Err
+	}
+	endpoints := make(map[string]string)
+	for ecosystem, endpoint := range cfg.Registries {
+		endpoints[ecosystem] = endpoint
+	}
+	for ecosystem, endpoint := range registries {
+		endpoints[ecosystem] = endpoint
+	}
+
+	type manifest struct {
+		rel     string
+		content string
+		pin     manifestPinner
+	}
+	var manifests []manifest
+	err := walkProjectFiles(dir, nil, func(rel string, data []byte) error {
+		if pin := manifestPinnerFor(rel); pin != nil {
+			manifests = append(manifests, manifest{rel: rel, content: string(data), pin: pin})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PinReport{}
+	pinner := &dependencyPinner{client: newRegistryClient(endpoints), report: report}
+	for _, m := range manifests {
+		pinner.manifest = m.rel
+		updated, err := m.pin(pinner, m.content)
+		if err != nil {
+			report.Dependencies = append(report.Dependencies, PinnedDependency{Manifest: m.rel, Problem: err.Error()})
+			continue
+		}
+		if updated == m.content {
+			continue
+		}
+		report.Manifests = append(report.Manifests, m.rel)
+		if dryRun {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(m.rel)), []byte(updated), 0644); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// dependencyPinner resolve as dependências de um manifesto e registra o resultado no relatório
+type dependencyPinner struct {
+	client   *registryClient
+	report   *PinReport
+	manifest string
+}
+
+// resolve escolhe a versão de uma dependência e retorna a nova especificação, montada por
+// format a partir da versão resolvida. Se a dependência não puder ser resolvida, a
+// especificação original é mantida e o problema registrado.
+func (p *dependencyPinner) resolve(ecosystem, name, from string, spec versionSpec, format func(resolved version, widened bool) string) string {
+	dep := PinnedDependency{Manifest: p.manifest, Ecosystem: ecosystem, Name: name, From: from, To: from}
+	if versions, err := p.client.Versions(ecosystem, name); err != nil {
+		dep.Problem = err.Error()
+	} else if resolved, widened, ok := resolveVersion(versions, spec); !ok {
+		dep.Problem = "nenhuma versão publicada compatível com " + from
+	} else {
+		dep.To, dep.Widened = format(resolved, widened), widened
+	}
+	p.report.Dependencies = append(p.report.Dependencies, dep)
+	return dep.To
+}
+
+// pinSpec resolve uma dependência com especificação semver ou PEP 440. Especificações
+// simples, como ^1.2.3, apontam para a versão resolvida; as compostas só são reescritas
+// quando nenhuma versão publicada as satisfaz, usando defaultOp.
+func (p *dependencyPinner) pinSpec(ecosystem, name, from string, spec versionSpec, defaultOp string) string {
+	return p.resolve(ecosystem, name, from, spec, func(resolved version, widened bool) string {
+		if widened || spec.simple {
+			return spec.rewrite(resolved, defaultOp)
+		}
+		return from
+	})
+}
+
+// check só verifica se o pacote existe, para dependências sem faixa de versão
+func (p *dependencyPinner) check(ecosystem, name, from string) {
+	p.resolve(ecosystem, name, from, versionSpec{sets: [][]versionBound{nil}, major: -1, pre: true}, func(version, bool) string {
+		return from
+	})
+}
+
+func pinPackageJSON(p *dependencyPinner, content string) (string, error) {
+	pkg, err := ParsePackageJSON([]byte(content))
+	if err != nil {
+		return "", err
+	}
+	changed := false
+	for _, field := range PackageDependencyFields {
+		deps, err := pkg.Dependencies(field)
+		if err != nil {
+			return "", err
+		}
+		fieldChanged := false
+		for i, dep := range deps {
+			spec, err := parseNPMSpec(dep.Range)
+			if err != nil {
+				// Tags como latest são resolvidas pelo npm; caminhos, repositórios e URLs não vêm do registro
+				if distTagPattern.MatchString(strings.TrimSpace(dep.Range)) {
+					p.check("npm", dep.Name, dep.Range)
+				}
+				continue
+			}
+			if to := p.pinSpec("npm", dep.Name, dep.Range, spec, "^"); to != dep.Range {
+				deps[i].Range = to
+				fieldChanged = true
+			}
+		}
+		if fieldChanged {
+			if err := pkg.SetDependencies(field, deps); err != nil {
+				return "", err
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return content, nil
+	}
+	out, err := pkg.Marshal(detectIndent(content))
+	return string(out), err
+}
+
+func pinGoMod(p *dependencyPinner, content string) (string, error) {
+	f, err := modfile.Parse(p.manifest, []byte(content), nil)
+	if err != nil {
+		return "", err
+	}
+	// Módulos substituídos por replace não vêm do registro
+	replaced := make(map[string]bool)
+	for _, r := range f.Replace {
+		replaced[r.Old.Path] = true
+	}
+
+	changed := false
+	for _, r := range f.Require {
+		current, ok := parseSemver(r.Mod.Version)
+		if replaced[r.Mod.Path] || !ok || strings.HasSuffix(r.Mod.Version, "+incompatible") {
+			continue
+		}
+		// No Go, versões compatíveis são as da mesma versão major
+		major := current.release[0]
+		spec := versionSpec{
+			sets:  [][]versionBound{{{">=", version{release: []int{major}}}, {"<", version{release: []int{major + 1}, pre: "0"}}}},
+			major: major,
+			pre:   current.pre != "",
+		}
+		to := p.resolve("go", r.Mod.Path, r.Mod.Version, spec, func(resolved version, widened bool) string {
+			return resolved.raw
+		})
+		if to != r.Mod.Version {
+			if err := f.AddRequire(r.Mod.Path, to); err != nil {
+				return "", err
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return content, nil
+	}
+	f.Cleanup()
+	out, err := f.Format()
+	return string(out), err
+}
+
+var requirementPattern = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*([^;]*?)\s*(;.*)?$`)
+
+// pinRequirement fixa a versão de um requisito PEP 508, como "requests[socks]>=2.0; python_version > '3.8'"
+func (p *dependencyPinner) pinRequirement(requirement string) string {
+	m := requirementPattern.FindStringSubmatchIndex(requirement)
+	if m == nil {
+		return requirement
+	}
+	name := requirement[m[2]:m[3]]
+	from := requirement[m[6]:m[7]]
+	switch {
+	case strings.HasPrefix(from, "@"):
+		// Referência direta a uma URL
+		return requirement
+	case from == "":
+		p.check("pypi", name, from)
+		return requirement
+	}
+	spec, err := parsePythonSpec(from, false)
+	if err != nil {
+		return requirement
+	}
+	to := p.pinSpec("pypi", name, from, spec, ">=")
+	return requirement[:m[6]] + to + requirement[m[7]:]
+}
+
+func pinRequirements(p *dependencyPinner, content string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		text, comment := line, ""
+		if j := strings.Index(line, " #"); j >= 0 {
+			text, comment = line[:j], line[j:]
+		}
+		trimmed := strings.TrimSpace(text)
+		// Opções (-r, -e, --index-url), comentários e URLs não são requisitos do registro
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") || strings.Contains(trimmed, "://") {
+			continue
+		}
+		lines[i] = p.pinRequirement(text) + comment
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Tipos de tabela de um manifesto TOML para a fixação de versões
+const (
+	tomlOtherTable = iota
+	// tomlDependencyTable tem uma dependência por chave, como [dependencies] no Cargo
+	tomlDependencyTable
+	// tomlDependencyEntry é a tabela de uma única dependência, como [dependencies.serde]
+	tomlDependencyEntry
+	// tomlRequirementArrays tem listas de requisitos PEP 508, como [project]
+	tomlRequirementArrays
+)
+
+// tomlSection descreve uma tabela de um manifesto TOML
+type tomlSection struct {
+	kind int
+	// name é o nome da dependência de uma tomlDependencyEntry
+	name string
+	// arrayKey limita as listas de requisitos de uma tomlRequirementArrays a uma chave
+	arrayKey string
+}
+
+var (
+	tomlHeaderPattern    = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(?:#.*)?$`)
+	tomlKeyPattern       = regexp.MustCompile(`^\s*("[^"]+"|'[^']+'|[A-Za-z0-9_.-]+)\s*=\s*(.*)$`)
+	tomlStringPattern    = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|'([^']*)'`)
+	tomlInlineVersion    = regexp.MustCompile(`\bversion\s*=\s*("[^"]*"|'[^']*')`)
+	tomlInlinePackage    = regexp.MustCompile(`\bpackage\s*=\s*("([^"]*)"|'([^']*)')`)
+	tomlInlineSource     = regexp.MustCompile(`\b(path|git|url|workspace)\s*=`)
+	tomlDependencyHeader = regexp.MustCompile(`^(?:.*\.)?(?:dependencies|dev-dependencies|build-dependencies)(?:\.("[^"]+"|'[^']+'|[A-Za-z0-9_-]+))?$`)
+)
+
+// tomlDependencyEditor reescreve as versões de dependências de um manifesto TOML linha a
+// linha, preservando a formatação e os comentários do arquivo
+type tomlDependencyEditor struct {
+	classify func(header string) tomlSection
+	// pinEntry recebe o nome e a especificação de uma dependência e retorna a nova especificação
+	pinEntry func(name, spec string) string
+	// pinRequirement recebe um requisito PEP 508 e retorna o requisito reescrito
+	pinRequirement func(requirement string) string
+}
+
+func unquoteTOMLKey(key string) string {
+	return strings.Trim(key, `"'`)
+}
+
+// replaceTOMLString troca o conteúdo do literal de texto em loc, mantendo as aspas originais
+func replaceTOMLString(line string, loc []int, value string) string {
+	return line[:loc[0]+1] + value + line[loc[1]-1:]
+}
+
+func (e tomlDependencyEditor) edit(content string) string {
+	lines := strings.Split(content, "\n")
+	section := tomlSection{}
+	inArray := false
+	// Na tabela de uma única dependência, a versão só é reescrita ao fim da tabela, quando
+	// se sabe que ela não vem de um caminho ou repositório
+	entryVersionLine, entrySource, entryPackage := -1, false, ""
+	flushEntry := func() {
+		if section.kind == tomlDependencyEntry && entryVersionLine >= 0 && !entrySource {
+			name := section.name
+			if entryPackage != "" {
+				name = entryPackage
+			}
+			line := lines[entryVersionLine]
+			if m := tomlKeyPattern.FindStringSubmatchIndex(line); m != nil {
+				if loc := tomlStringPattern.FindStringIndex(line[m[4]:]); loc != nil {
+					loc[0], loc[1] = loc[0]+m[4], loc[1]+m[4]
+					lines[entryVersionLine] = replaceTOMLString(line, loc, e.pinEntry(name, line[loc[0]+1:loc[1]-1]))
+				}
+			}
+		}
+		entryVersionLine, entrySource, entryPackage = -1, false, ""
+	}
+
+	for i, line := range lines {
+		if inArray {
+			lines[i] = e.editRequirements(line)
+			inArray = !closesTOMLArray(line)
+			continue
+		}
+		if m := tomlHeaderPattern.FindStringSubmatch(line); m != nil {
+			flushEntry()
+			section = e.classify(m[1])
+			continue
+		}
+		m := tomlKeyPattern.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		rawKey := line[m[2]:m[3]]
+		key := unquoteTOMLKey(rawKey)
+		value := line[m[4]:m[5]]
+
+		switch section.kind {
+		case tomlDependencyTable:
+			// Chaves pontuadas sem aspas, como serde.workspace, não são uma dependência inteira
+			if key == rawKey && strings.Contains(key, ".") {
+				continue
+			}
+			if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+				if loc := tomlStringPattern.FindStringIndex(value); loc != nil {
+					loc[0], loc[1] = loc[0]+m[4], loc[1]+m[4]
+					lines[i] = replaceTOMLString(line, loc, e.pinEntry(key, line[loc[0]+1:loc[1]-1]))
+				}
+			} else if strings.HasPrefix(value, "{") && !tomlInlineSource.MatchString(value) {
+				name := key
+				if pkg := tomlInlinePackage.FindStringSubmatch(value); pkg != nil {
+					name = pkg[2] + pkg[3]
+				}
+				if v := tomlInlineVersion.FindStringSubmatchIndex(value); v != nil {
+					loc := []int{v[2] + m[4], v[3] + m[4]}
+					lines[i] = replaceTOMLString(line, loc, e.pinEntry(name, line[loc[0]+1:loc[1]-1]))
+				}
+			}
+		case tomlDependencyEntry:
+			switch key {
+			case "version":
+				entryVersionLine = i
+			case "package":
+				entryPackage = unquoteTOMLKey(strings.TrimSpace(value))
+			case "path", "git", "url", "workspace":
+				entrySource = true
+			}
+		case tomlRequirementArrays:
+			if (section.arrayKey == "" || section.arrayKey == key) && strings.HasPrefix(value, "[") {
+				lines[i] = line[:m[4]] + e.editRequirements(value)
+				inArray = !closesTOMLArray(value)
+			}
+		}
+	}
+	flushEntry()
+	return strings.Join(lines, "\n")
+}
+
+// closesTOMLArray indica se a linha fecha a lista, ignorando colchetes em textos e comentários
+func closesTOMLArray(line string) bool {
+	code := tomlStringPattern.ReplaceAllString(line, `""`)
+	if i := strings.Index(code, "#"); i >= 0 {
+		code = code[:i]
+	}
+	return strings.Contains(code, "]")
+}
+
+// editRequirements reescreve cada requisito PEP 508 entre aspas de uma linha
+func (e tomlDependencyEditor) editRequirements(line string) string {
+	return tomlStringPattern.ReplaceAllStringFunc(line, func(literal string) string {
+		return literal[:1] + e.pinRequirement(literal[1:len(literal)-1]) + literal[len(literal)-1:]
+	})
+}
+
+func pinCargo(p *dependencyPinner, content string) (string, error) {
+	editor := tomlDependencyEditor{
+		classify: func(header string) tomlSection {
+			m := tomlDependencyHeader.FindStringSubmatch(header)
+			switch {
+			case m == nil:
+				return tomlSection{}
+			case m[1] != "":
+				return tomlSection{kind: tomlDependencyEntry, name: unquoteTOMLKey(m[1])}
+			}
+			return tomlSection{kind: tomlDependencyTable}
+		},
+		pinEntry: func(name, from string) string {
+			spec, err := parseCargoSpec(from)
+			if err != nil {
+				return from
+			}
+			return p.pinSpec("cargo", name, from, spec, "")
+		},
+	}
+	return editor.edit(content), nil
+}
+
+func pinPyproject(p *dependencyPinner, content string) (string, error) {
+	editor := tomlDependencyEditor{
+		classify: func(header string) tomlSection {
+			switch {
+			case header == "project":
+				return tomlSection{kind: tomlRequirementArrays, arrayKey: "dependencies"}
+			case header == "project.optional-dependencies" || header == "dependency-groups":
+				return tomlSection{kind: tomlRequirementArrays}
+			case header == "tool.poetry.dependencies" || header == "tool.poetry.dev-dependencies" ||
+				strings.HasPrefix(header, "tool.poetry.group.") && strings.HasSuffix(header, ".dependencies"):
+				return tomlSection{kind: tomlDependencyTable}
+			}
+			return tomlSection{}
+		},
+		pinEntry: func(name, from string) string {
+			// No poetry, a chave python é a versão do interpretador
+			if name == "python" {
+				return from
+			}
+			spec, err := parsePythonSpec(from, true)
+			if err != nil {
+				return from
+			}
+			if strings.TrimSpace(from) == "*" {
+				p.check("pypi", name, from)
+				return from
+			}
+			return p.pinSpec("pypi", name, from, spec, "^")
+		},
+		pinRequirement: p.pinRequirement,
+	}
+	return editor.edit(content), nil
+}
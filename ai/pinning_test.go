@@ -0,0 +1,351 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeTestFiles grava os arquivos em dir, criando os diretórios intermediários
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testRegistries monta registros locais de cada ecossistema, com os mesmos caminhos dos
+// registros reais, e retorna os endpoints para PinDependencies
+func testRegistries(t *testing.T) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"npm/express":     `{"versions":{"4.17.1":{},"4.21.2":{},"5.0.1":{}}}`,
+		"npm/left-pad":    `{"versions":{"1.0.0":{},"1.3.0":{},"2.0.0-beta.1":{}}}`,
+		"npm/@types/node": `{"versions":{"20.1.0":{},"20.11.5":{},"22.0.0":{}}}`,
+		"npm/lodash":      `{"versions":{"4.17.21":{}}}`,
+
+		"go/github.com/pkg/errors/@v/list": "v0.8.0\nv0.9.1\n",
+		"go/golang.org/x/text/@v/list":     "v0.14.0\nv0.15.0\nv1.0.0-rc.1\n",
+
+		"pypi/requests/index.json": `{"versions":["2.28.0","2.31.0","3.0.0rc1"]}`,
+		"pypi/django/index.json":   `{"versions":["4.1.0","4.2.0","4.2.11","5.0.3"]}`,
+		"pypi/flask/index.json":    `{"files":[{"filename":"flask-3.0.2.tar.gz"},{"filename":"flask-3.0.3-py3-none-any.whl","yanked":false}]}`,
+
+		"cargo/se/rd/serde": `{"vers":"1.0.100"}` + "\n" + `{"vers":"1.0.197"}` + "\n" + `{"vers":"1.0.198","yanked":true}` + "\n",
+		"cargo/ra/nd/rand":  `{"vers":"0.8.4"}` + "\n" + `{"vers":"0.8.5"}` + "\n" + `{"vers":"0.9.0"}` + "\n",
+		"cargo/to/ki/tokio": `{"vers":"1.36.0"}` + "\n",
+	})
+	registries := make(map[string]string)
+	for _, ecosystem := range []string{"npm", "go", "pypi", "cargo"} {
+		registries[ecosystem] = filepath.Join(dir, ecosystem)
+	}
+	return registries
+}
+
+func TestPinDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		content  string
+		want     string
+		problems []string
+	}{
+		{
+			name:     "package.json",
+			manifest: "package.json",
+			content: `{
+  "name": "app",
+  "dependencies": {
+    "express": "^4.17.0",
+    "left-pad": "^1.0.0",
+    "lodash": "latest",
+    "local": "file:../local",
+    "missing": "^1.0.0"
+  },
+  "devDependencies": {
+    "@types/node": "^20.0.0"
+  }
+}
+`,
+			want: `{
+  "name": "app",
+  "dependencies": {
+    "express": "^4.21.2",
+    "left-pad": "^1.3.0",
+    "lodash": "latest",
+    "local": "file:../local",
+    "missing": "^1.0.0"
+  },
+  "devDependencies": {
+    "@types/node": "^20.11.5"
+  }
+}
+`,
+			problems: []string{"missing"},
+		},
+		{
+			name:     "go.mod",
+			manifest: "go.mod",
+			content:  "module example.com/app\n\ngo 1.21\n\nrequire (\n\tgithub.com/pkg/errors v0.8.0\n\tgolang.org/x/text v0.14.0\n\texample.com/local v1.0.0\n)\n\nreplace example.com/local => ../local\n",
+			want:     "module example.com/app\n\ngo 1.21\n\nrequire (\n\tgithub.com/pkg/errors v0.9.1\n\tgolang.org/x/text v0.15.0\n\texample.com/local v1.0.0\n)\n\nreplace example.com/local => ../local\n",
+		},
+		{
+			name:     "requirements.txt",
+			manifest: "requirements.txt",
+			content:  "-r base.txt\nrequests>=2.28  # http\ndjango==4.2.99\nflask\nrequests[socks] >= 2.0 ; python_version > '3.8'\ngit+https://github.com/user/repo.git\n# comentário\nmissing==1.0\n",
+			want:     "-r base.txt\nrequests>=2.31.0  # http\ndjango==4.2.11\nflask\nrequests[socks] >=2.31.0 ; python_version > '3.8'\ngit+https://github.com/user/repo.git\n# comentário\nmissing==1.0\n",
+			problems: []string{"missing"},
+		},
+		{
+			name:     "pyproject.toml",
+			manifest: "pyproject.toml",
+			content: `[project]
+name = "app"
+version = "0.1.0"
+dependencies = [
+    "requests>=2.0",
+    "django~=4.1",
+]
+
+[project.optional-dependencies]
+dev = ["flask"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.28"
+django = { version = "^4.0", extras = ["bcrypt"] }
+local = { path = "../local" }
+
+[tool.poetry.group.dev.dependencies]
+flask = "*"
+`,
+			want: `[project]
+name = "app"
+version = "0.1.0"
+dependencies = [
+    "requests>=2.31.0",
+    "django~=4.2",
+]
+
+[project.optional-dependencies]
+dev = ["flask"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.31.0"
+django = { version = "^4.2.11", extras = ["bcrypt"] }
+local = { path = "../local" }
+
+[tool.poetry.group.dev.dependencies]
+flask = "*"
+`,
+		},
+		{
+			name:     "Cargo.toml",
+			manifest: "Cargo.toml",
+			content: `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"  # serialização
+rand = { version = "0.8.4", features = ["small_rng"] }
+local = { path = "../local", version = "0.1" }
+tokio = { workspace = true }
+
+[dependencies.async]
+package = "tokio"
+version = "1"
+
+[dependencies.vendored]
+version = "1"
+path = "vendor/x"
+
+[target.'cfg(unix)'.dev-dependencies]
+rand = '0.8'
+`,
+			want: `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0.197"  # serialização
+rand = { version = "0.8.5", features = ["small_rng"] }
+local = { path = "../local", version = "0.1" }
+tokio = { workspace = true }
+
+[dependencies.async]
+package = "tokio"
+version = "1.36.0"
+
+[dependencies.vendored]
+version = "1"
+path = "vendor/x"
+
+[target.'cfg(unix)'.dev-dependencies]
+rand = '0.8.5'
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			registries := testRegistries(t)
+			for _, dryRun := range []bool{true, false} {
+				dir := t.TempDir()
+				writeTestFiles(t, dir, map[string]string{tt.manifest: tt.content})
+				report, err := PinDependencies(dir, registries, dryRun)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, err := os.ReadFile(filepath.Join(dir, tt.manifest))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := tt.want
+				if dryRun {
+					want = tt.content
+				}
+				if string(data) != want {
+					t.Errorf("dryRun %v: manifesto =\n%s\nesperado:\n%s", dryRun, data, want)
+				}
+				if !reflect.DeepEqual(report.Manifests, []string{tt.manifest}) {
+					t.Errorf("dryRun %v: manifestos alterados = %q", dryRun, report.Manifests)
+				}
+				var problems []string
+				for _, dep := range report.Problems() {
+					problems = append(problems, dep.Name)
+				}
+				if !reflect.DeepEqual(problems, tt.problems) {
+					t.Errorf("problemas = %q, esperado %q: %+v", problems, tt.problems, report.Problems())
+				}
+			}
+		})
+	}
+}
+
+func TestPinDependenciesWidened(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"package.json": `{"dependencies":{"express":"^4.99.0","left-pad":"^1.3.0"}}`})
+	report, err := PinDependencies(dir, testRegistries(t), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changed []string
+	for _, dep := range report.Changed() {
+		changed = append(changed, dep.Name+" "+dep.From+" -> "+dep.To)
+		if !dep.Widened {
+			t.Errorf("%s: Widened = false", dep.Name)
+		}
+	}
+	if want := []string{"express ^4.99.0 -> ^4.21.2"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("alteradas = %q, esperado %q", changed, want)
+	}
+}
+
+func TestPinDependenciesInvalidConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeTestFiles(t, home, map[string]string{".zion/config.yaml": "registries: [npm\n"})
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"package.json": `{"dependencies":{"express":"^4.17.0"}}`})
+	if _, err := PinDependencies(dir, testRegistries(t), false); err == nil || !strings.Contains(err.Error(), "config.yaml") {
+		t.Errorf("erro = %v, esperado erro do config.yaml", err)
+	}
+}
+
+func TestTOMLDependencyEditor(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		entries []string
+	}{
+		{
+			name:    "tabela de dependências",
+			content: "[deps]\na = \"1\"\n\"b.c\" = '2'\nd = { version = \"3\" }\ne = { git = \"https://x\", version = \"4\" }\nf.workspace = true\n",
+			want:    "[deps]\na = \"pin(1)\"\n\"b.c\" = 'pin(2)'\nd = { version = \"pin(3)\" }\ne = { git = \"https://x\", version = \"4\" }\nf.workspace = true\n",
+			entries: []string{"a 1", "b.c 2", "d 3"},
+		},
+		{
+			name:    "pacote renomeado",
+			content: "[deps]\nx = { package = \"real\", version = \"1\" }\n[deps.y]\nversion = \"2\"\npackage = 'other'\n",
+			want:    "[deps]\nx = { package = \"real\", version = \"pin(1)\" }\n[deps.y]\nversion = \"pin(2)\"\npackage = 'other'\n",
+			entries: []string{"real 1", "other 2"},
+		},
+		{
+			name:    "tabela de uma dependência com origem local",
+			content: "[deps.y]\nversion = \"2\"\ngit = \"https://x\"\n[deps.z]\nversion = \"3\" # fixa\n",
+			want:    "[deps.y]\nversion = \"2\"\ngit = \"https://x\"\n[deps.z]\nversion = \"pin(3)\" # fixa\n",
+			entries: []string{"z 3"},
+		},
+		{
+			name:    "outras tabelas não mudam",
+			content: "[package]\nversion = \"1\"\n[[bin]]\nname = \"x\"\n",
+			want:    "[package]\nversion = \"1\"\n[[bin]]\nname = \"x\"\n",
+		},
+		{
+			name:    "listas de requisitos em várias linhas",
+			content: "[reqs]\nlist = [\n  \"a>=1\",  # comentário ]\n  'b', \"c\"\n]\nother = \"x\"\n",
+			want:    "[reqs]\nlist = [\n  \"req(a>=1)\",  # comentário ]\n  'req(b)', \"req(c)\"\n]\nother = \"x\"\n",
+		},
+		{
+			name:    "lista em uma linha com colchete no texto",
+			content: "[reqs]\nlist = [\"a[x]>=1\", \"b\"]\nnext = \"y\"\n",
+			want:    "[reqs]\nlist = [\"req(a[x]>=1)\", \"req(b)\"]\nnext = \"y\"\n",
+		},
+		{
+			name:    "cabeçalho com comentário",
+			content: "[deps] # principais\na = \"1\"\n",
+			want:    "[deps] # principais\na = \"pin(1)\"\n",
+			entries: []string{"a 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []string
+			editor := tomlDependencyEditor{
+				classify: func(header string) tomlSection {
+					switch {
+					case header == "deps":
+						return tomlSection{kind: tomlDependencyTable}
+					case strings.HasPrefix(header, "deps."):
+						return tomlSection{kind: tomlDependencyEntry, name: strings.TrimPrefix(header, "deps.")}
+					case header == "reqs":
+						return tomlSection{kind: tomlRequirementArrays, arrayKey: "list"}
+					}
+					return tomlSection{}
+				},
+				pinEntry: func(name, spec string) string {
+					entries = append(entries, name+" "+spec)
+					return "pin(" + spec + ")"
+				},
+				pinRequirement: func(requirement string) string {
+					return "req(" + requirement + ")"
+				},
+			}
+			if got := editor.edit(tt.content); got != tt.want {
+				t.Errorf("edit =\n%s\nesperado:\n%s", got, tt.want)
+			}
+			sort.Strings(entries)
+			want := append([]string(nil), tt.entries...)
+			sort.Strings(want)
+			if !reflect.DeepEqual(entries, want) && (len(entries) > 0 || len(want) > 0) {
+				t.Errorf("dependências = %q, esperado %q", entries, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+)
+
+// skippedDirs são diretórios que nunca fazem parte do manifesto de um projeto existente.
+// Diretórios de saída como build, dist, vendor e target só são ignorados quando estão no
+// .gitignore, pois em vários projetos (build/ e vendor/ em Go e Java, por exemplo) são código.
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".zion":        true,
+	"node_modules": true,
+}
+
+// maxProjectFileSize é o tamanho máximo de um arquivo lido de um projeto existente
+const maxProjectFileSize = 100 * 1024
+
+// walkProjectFiles percorre os arquivos de texto de um projeto, ignorando os diretórios de
+// skippedDirs, caminhos do .gitignore e do .zionignore, arquivos binários e arquivos maiores que maxProjectFileSize
+func walkProjectFiles(dir string, onDir func(rel string), onFile func(rel string, data []byte) error) error {
+	return walkProjectTree(dir, onDir, func(rel string, data []byte, mode os.FileMode) error {
+		if !utf8.Valid(data) {
+			return nil
+		}
+		return onFile(rel, data)
+	})
+}
+
+// walkProjectTree percorre o projeto como walkProjectFiles, mas inclui os arquivos que não
+// são UTF-8 e informa as permissões de cada arquivo
+func walkProjectTree(dir string, onDir func(rel string), onFile func(rel string, data []byte, mode os.FileMode) error) error {
+	ignore := LoadIgnoreMatcher(dir, ".gitignore", ".zionignore")
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if skippedDirs[d.Name()] || ignore.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			if onDir != nil {
+				onDir(rel)
+			}
+			return nil
+		}
+		if ignore.Match(rel, false) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxProjectFileSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("erro ao ler %s: %v", rel, err)
+		}
+		return onFile(rel, data, info.Mode().Perm())
+	})
+}
+
+// ReadProjectManifest lê um projeto existente do disco e o converte no mesmo formato
+// JSON de manifesto retornado pela geração. Arquivos binários e grandes são ignorados;
+// arquivos executáveis levam o modo, como nas entradas tipadas da geração.
+func ReadProjectManifest(dir string) (string, error) {
+	var scaffoldResp ScaffoldResponse
+	scaffoldResp.Structure.Files = make(map[string]interface{})
+
+	err := walkProjectTree(dir, func(rel string) {
+		scaffoldResp.Structure.Directories = append(scaffoldResp.Structure.Directories, rel)
+	}, func(rel string, data []byte, mode os.FileMode) error {
+		switch {
+		case !utf8.Valid(data):
+		case mode&0111 != 0:
+			scaffoldResp.Structure.Files[rel] = map[string]interface{}{"content": string(data), "mode": fmt.Sprintf("%04o", mode.Perm())}
+		default:
+			scaffoldResp.Structure.Files[rel] = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler projeto: %v", err)
+	}
+
+	sort.Strings(scaffoldResp.Structure.Directories)
+	data, err := json.MarshalIndent(scaffoldResp, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar manifesto: %v", err)
+	}
+	return string(data), nil
+}
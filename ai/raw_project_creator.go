@@ -102,10 +102,7 @@ func CreateProjectFromRawJSON(projectName string, jsonStr string) error {
 		content := filesContent[pos:contentEnd]
 		
 		// Decodificar caracteres escapados
-		content = strings.ReplaceAll(content, "\\\"", "\"")
-		content = strings.ReplaceAll(content, "\\n", "\n")
-		content = strings.ReplaceAll(content, "\\t", "\t")
-		content = strings.ReplaceAll(content, "\\\\", "\\")
+		content = ProcessEscapedChars(content)
 		
 		// Adicionar ao mapa
 		fileMap[fileName] = content
@@ -4,18 +4,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"zion/i18n"
 )
 
 // SaveRawResponse salva a resposta bruta da API em um arquivo JSON
 // para que o usuário possa processá-la manualmente posteriormente
 func SaveRawResponse(projectName, response string) error {
+	// A resposta passa pela mesma verificação de segredos dos arquivos do projeto; se ela
+	// não puder ser feita, nada é gravado
+	response, report, err := ScanRawResponse(response)
+	if report != nil {
+		printSecretReport(report)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Criar o diretório do projeto se não existir
 	if err := os.MkdirAll(projectName, 0755); err != nil {
 		return fmt.Errorf("erro ao criar diretório do projeto: %v", err)
 	}
 
 	// Caminho para o arquivo de resposta
-	responsePath := filepath.Join(projectName, "zion_response.json")
+	responsePath := filepath.Join(projectName, rawResponseFile)
 	
 	// Salvar a resposta bruta no arquivo
 	if err := os.WriteFile(responsePath, []byte(response), 0644); err != nil {
@@ -24,52 +35,14 @@ func SaveRawResponse(projectName, response string) error {
 
 	// Criar um README explicando como usar a resposta
 	readmePath := filepath.Join(projectName, "README.md")
-	readmeContent := `# Projeto gerado pelo Zion
-
-Este projeto foi gerado pelo Zion, mas devido a limitações no parsing do JSON com caracteres especiais como '@',
-a resposta bruta da API foi salva no arquivo 'zion_response.json'.
-
-## Como proceder
-
-1. Examine o arquivo 'zion_response.json' para ver a estrutura do projeto gerada pela IA.
-2. Crie manualmente os arquivos e diretórios conforme especificado na resposta.
-3. Para projetos TypeScript com pacotes npm que contêm '@', você pode iniciar um projeto com:
-
-` + "```bash" + `
-npm init -y
-npm install express
-npm install --save-dev typescript @types/express @types/node ts-node-dev
-` + "```" + `
-
-4. Crie um arquivo tsconfig.json básico:
-
-` + "```json" + `
-{
-  "compilerOptions": {
-    "target": "es2016",
-    "module": "commonjs",
-    "esModuleInterop": true,
-    "forceConsistentCasingInFileNames": true,
-    "strict": true,
-    "skipLibCheck": true,
-    "outDir": "dist"
-  }
-}
-` + "```" + `
-
-5. Crie a estrutura de diretórios e arquivos conforme especificado na resposta.
-
-## Próximos passos
-
-Em versões futuras do Zion, este processo será automatizado para lidar corretamente com caracteres especiais.
-`
+	readmeContent := i18n.Doc("raw_response_readme.md", DocLanguage())
 
 	if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
 		return fmt.Errorf("erro ao criar README: %v", err)
 	}
 
-	fmt.Println("Resposta bruta salva em:", responsePath)
-	fmt.Println("README com instruções criado em:", readmePath)
+	fmt.Print(i18n.T("ai.raw.saved", responsePath))
+	fmt.Print(i18n.T("ai.raw.readme", readmePath))
 	
 	return nil
 }
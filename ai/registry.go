@@ -0,0 +1,288 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+// maxRegistryResponse limita o tamanho de uma resposta do registro
+const maxRegistryResponse = 64 << 20
+
+// registryTimeout limita o tempo de cada consulta ao registro
+const registryTimeout = 30 * time.Second
+
+// errPackageNotFound indica que o registro não conhece o pacote
+var errPackageNotFound = errors.New("pacote desconhecido no registro")
+
+// registryClient consulta os registros de pacotes de cada ecossistema. Cada endpoint é a
+// URL de um registro (ou de um espelho local, como Verdaccio, um GOPROXY ou devpi) ou um
+// diretório local com os mesmos caminhos do registro. Em um diretório, um caminho que
+// corresponde a um subdiretório é lido do index.json dentro dele.
+type registryClient struct {
+	endpoints map[string]string
+	client    *http.Client
+	versions  map[string][]version
+}
+
+func newRegistryClient(endpoints map[string]string) *registryClient {
+	return &registryClient{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: registryTimeout},
+		versions:  make(map[string][]version),
+	}
+}
+
+// fetch lê o caminho rel do registro do ecossistema
+func (c *registryClient) fetch(ecosystem, rel, accept string) ([]byte, error) {
+	endpoint := c.endpoints[ecosystem]
+	if endpoint == "" {
+		return nil, fmt.Errorf("nenhum registro configurado para %s", ecosystem)
+	}
+
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		url := strings.TrimRight(endpoint, "/") + "/" + rel
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao consultar o registro: %v", err)
+		}
+		defer resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+			return nil, errPackageNotFound
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("registro respondeu %s para %s", resp.Status, url)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxRegistryResponse))
+	}
+
+	file := filepath.Join(strings.TrimPrefix(endpoint, "file://"), filepath.FromSlash(rel))
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil, errPackageNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		file = filepath.Join(file, "index.json")
+	}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, errPackageNotFound
+	}
+	return data, err
+}
+
+// Versions retorna as versões publicadas de um pacote, com cache por execução
+func (c *registryClient) Versions(ecosystem, name string) ([]version, error) {
+	key := ecosystem + "\x00" + name
+	if versions, ok := c.versions[key]; ok {
+		return versions, nil
+	}
+
+	var versions []version
+	var err error
+	switch ecosystem {
+	case "npm":
+		versions, err = c.npmVersions(name)
+	case "go":
+		versions, err = c.goVersions(name)
+	case "pypi":
+		versions, err = c.pypiVersions(name)
+	case "cargo":
+		versions, err = c.cargoVersions(name)
+	default:
+		err = fmt.Errorf("ecossistema desconhecido: %s", ecosystem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, errPackageNotFound
+	}
+	c.versions[key] = versions
+	return versions, nil
+}
+
+// npmVersions lê o documento do pacote no formato resumido do registro npm
+func (c *registryClient) npmVersions(name string) ([]version, error) {
+	data, err := c.fetch("npm", name, "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8")
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("resposta inválida do registro npm para %s: %v", name, err)
+	}
+	var versions []version
+	for text := range doc.Versions {
+		if v, ok := parseSemver(text); ok {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// goVersions usa o protocolo GOPROXY: a lista de versões em @v/list e, para módulos sem
+// versões marcadas, a versão de @latest
+func (c *registryClient) goVersions(modulePath string) ([]version, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.fetch("go", escaped+"/@v/list", "")
+	if err != nil {
+		return nil, err
+	}
+	var versions []version
+	for _, line := range strings.Fields(string(data)) {
+		if v, ok := parseSemver(line); ok && !strings.HasSuffix(line, "+incompatible") {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) > 0 {
+		return versions, nil
+	}
+
+	data, err = c.fetch("go", escaped+"/@latest", "")
+	if err != nil {
+		return nil, err
+	}
+	var info struct{ Version string }
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("resposta inválida do GOPROXY para %s: %v", modulePath, err)
+	}
+	if v, ok := parseSemver(info.Version); ok {
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+var (
+	pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+	simpleIndexLink      = regexp.MustCompile(`<a[^>]*>([^<]+)</a>`)
+)
+
+// normalizePythonName normaliza o nome de um pacote Python como o PEP 503
+func normalizePythonName(name string) string {
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// pypiVersions usa a API simples do PyPI (PEP 503/691), que também é servida por devpi.
+// As versões vêm da lista versions (PEP 700) ou dos nomes dos arquivos publicados.
+func (c *registryClient) pypiVersions(name string) ([]version, error) {
+	data, err := c.fetch("pypi", normalizePythonName(name)+"/", "application/vnd.pypi.simple.v1+json, text/html; q=0.1")
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	var doc struct {
+		Versions []string `json:"versions"`
+		Files    []struct {
+			Filename string      `json:"filename"`
+			Yanked   interface{} `json:"yanked"`
+		} `json:"files"`
+	}
+	if json.Unmarshal(data, &doc) == nil {
+		texts = doc.Versions
+		if len(texts) == 0 {
+			for _, file := range doc.Files {
+				// yanked é false, true ou o motivo da remoção
+				if file.Yanked == nil || file.Yanked == false {
+					texts = append(texts, distributionVersion(file.Filename))
+				}
+			}
+		}
+	} else {
+		for _, match := range simpleIndexLink.FindAllSubmatch(data, -1) {
+			texts = append(texts, distributionVersion(string(bytes.TrimSpace(match[1]))))
+		}
+	}
+
+	var versions []version
+	for _, text := range uniqueStrings(texts) {
+		if v, ok := parsePEP440(text); ok {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// distributionVersion extrai a versão do nome de um wheel ou de uma distribuição de código
+func distributionVersion(filename string) string {
+	if strings.HasSuffix(filename, ".whl") {
+		if parts := strings.Split(filename, "-"); len(parts) >= 2 {
+			return parts[1]
+		}
+		return ""
+	}
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".tgz", ".zip"} {
+		if strings.HasSuffix(filename, ext) {
+			base := strings.TrimSuffix(filename, ext)
+			if i := strings.LastIndex(base, "-"); i >= 0 {
+				return base[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// cargoIndexPath retorna o caminho de um crate no índice esparso do Cargo
+func cargoIndexPath(name string) string {
+	name = strings.ToLower(name)
+	switch len(name) {
+	case 1:
+		return path.Join("1", name)
+	case 2:
+		return path.Join("2", name)
+	case 3:
+		return path.Join("3", name[:1], name)
+	}
+	return path.Join(name[:2], name[2:4], name)
+}
+
+// cargoVersions lê o índice esparso do Cargo: uma linha JSON por versão publicada
+func (c *registryClient) cargoVersions(name string) ([]version, error) {
+	data, err := c.fetch("cargo", cargoIndexPath(name), "")
+	if err != nil {
+		return nil, err
+	}
+	var versions []version
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxRegistryResponse)
+	for scanner.Scan() {
+		var entry struct {
+			Vers   string `json:"vers"`
+			Yanked bool   `json:"yanked"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Yanked {
+			continue
+		}
+		if v, ok := parseSemver(entry.Vers); ok {
+			versions = append(versions, v)
+		}
+	}
+	return versions, scanner.Err()
+}
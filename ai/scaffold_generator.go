@@ -16,34 +16,35 @@ func CreateProjectStructure(projectName, jsonResponse string) error {
 	jsonContent := extractJSONContent(jsonResponse)
 	fmt.Printf("Conteúdo JSON extraído:\n%s\n", jsonContent)
 
-	// Decodificar o JSON
+	// Decodificar o JSON, mantendo a ordem das chaves do conteúdo dos arquivos JSON
 	var scaffoldResponse ScaffoldResponse
-	err := json.Unmarshal([]byte(jsonContent), &scaffoldResponse)
-	if err != nil {
+	var rawResponse rawScaffoldResponse
+	err := json.Unmarshal([]byte(jsonContent), &rawResponse)
+	if err == nil {
+		scaffoldResponse.Structure.Directories = rawResponse.Structure.Directories
+		scaffoldResponse.Structure.Files = make(map[string]interface{})
+		for filePath, raw := range rawResponse.Structure.Files {
+			scaffoldResponse.Structure.Files[filePath] = orderedValue(raw)
+		}
+	} else {
 		fmt.Printf("Erro ao decodificar JSON: %v\nTentando processamento alternativo...\n", err)
 
-		// Se falhar, tenta usar o processador específico para package.json com @types
-		if strings.Contains(jsonContent, "@types/") {
-			fmt.Println("Detectado pacote npm com @, aplicando correção específica...")
-			scaffoldResponse = ProcessNpmPackageJson(jsonContent)
-		} else {
-			// Tenta extrair usando regex
-			fmt.Println("Tentando extrair estrutura usando regex...")
-			diretoriosExtraidos, arquivosExtraidos := ExtractProjectStructure(jsonContent) // Use jsonContent aqui
-
-			if len(diretoriosExtraidos) > 0 || len(arquivosExtraidos) > 0 {
-				fmt.Printf("Estrutura extraída via regex: %d diretórios, %d arquivos\n",
-					len(diretoriosExtraidos), len(arquivosExtraidos))
-
-				// Preenche a estrutura manualmente
-				scaffoldResponse.Structure.Directories = diretoriosExtraidos
-				scaffoldResponse.Structure.Files = make(map[string]interface{}) // Criar o mapa correto
-				for k, v := range arquivosExtraidos {
-					scaffoldResponse.Structure.Files[k] = v
-				}
-			} else {
-				return fmt.Errorf("não foi possível extrair a estrutura do projeto: %v", err)
+		// Tenta extrair usando regex
+		fmt.Println("Tentando extrair estrutura usando regex...")
+		diretoriosExtraidos, arquivosExtraidos := ExtractProjectStructure(jsonContent) // Use jsonContent aqui
+
+		if len(diretoriosExtraidos) > 0 || len(arquivosExtraidos) > 0 {
+			fmt.Printf("Estrutura extraída via regex: %d diretórios, %d arquivos\n",
+				len(diretoriosExtraidos), len(arquivosExtraidos))
+
+			// Preenche a estrutura manualmente
+			scaffoldResponse.Structure.Directories = diretoriosExtraidos
+			scaffoldResponse.Structure.Files = make(map[string]interface{}) // Criar o mapa correto
+			for k, v := range arquivosExtraidos {
+				scaffoldResponse.Structure.Files[k] = v
 			}
+		} else {
+			return fmt.Errorf("não foi possível extrair a estrutura do projeto: %v", err)
 		}
 	}
 
@@ -79,10 +80,11 @@ func CreateProjectStructure(projectName, jsonResponse string) error {
 		if strContent, ok := content.(string); ok {
 			contentBytes = []byte(strContent)
 		} else {
-			contentBytes, err = json.MarshalIndent(content, "", "   ") // Alteração aqui para três espaços
+			formatted, err := formatJSONContent(filePath, content)
 			if err != nil {
 				return fmt.Errorf("erro ao serializar conteúdo para %s: %v", filePath, err)
 			}
+			contentBytes = []byte(formatted)
 		}
 		if err := os.WriteFile(fullPath, contentBytes, 0644); err != nil {
 			return fmt.Errorf("erro ao criar arquivo %s: %v", filePath, err)
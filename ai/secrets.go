@@ -0,0 +1,539 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"zion/config"
+	"zion/i18n"
+)
+
+// Ações para os segredos encontrados nos arquivos gerados
+const (
+	// SecretWarn aponta os segredos e mantém os valores (o padrão)
+	SecretWarn = "warn"
+	// SecretRedact troca os valores por SecretPlaceholder
+	SecretRedact = "redact"
+	// SecretAbort interrompe a criação do projeto sem gravar nenhum arquivo
+	SecretAbort = "abort"
+)
+
+// SecretActions são as ações aceitas em secrets.action no config.yaml e na flag --secrets
+var SecretActions = []string{SecretWarn, SecretRedact, SecretAbort}
+
+// SecretPlaceholder substitui os valores dos segredos removidos
+const SecretPlaceholder = "CHANGE_ME"
+
+// ErrSecretsFound é retornado quando a ação é abort e algum segredo foi encontrado
+var ErrSecretsFound = errors.New("possíveis segredos nos arquivos gerados; nenhum arquivo foi gravado")
+
+// secretRule é uma regra de detecção. O grupo "secret" da expressão, quando existe,
+// delimita o valor; senão, o valor é o trecho inteiro.
+type secretRule struct {
+	name    string
+	pattern *regexp.Regexp
+	// configOnly restringe a regra aos arquivos de configuração e .env
+	configOnly bool
+	// minEntropy é a entropia mínima, em bits por caractere, para que o valor seja apontado
+	minEntropy float64
+}
+
+// secretRules são as regras embutidas, dos formatos de credenciais mais comuns
+var secretRules = []secretRule{
+	{name: "private-key", pattern: regexp.MustCompile(`-----BEGIN[A-Z ]*PRIVATE KEY(?: BLOCK)?-----[\s\S]*?-----END[A-Z ]*PRIVATE KEY(?: BLOCK)?-----`)},
+	{name: "aws-access-key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "aws-secret-key", pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`)},
+	{name: "github-token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{name: "gitlab-token", pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{name: "slack-token", pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{name: "stripe-key", pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}\b`)},
+	{name: "google-api-key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "openai-key", pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{32,}\b`)},
+	{name: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{name: "url-password", minEntropy: 2.5, pattern: regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@"']+:(?P<secret>[^\s@/"']+)@`)},
+	{name: "generic-secret", configOnly: true, minEntropy: 3.0, pattern: regexp.MustCompile(`(?i)[\w.-]*(?:password|passwd|pwd|secret|token|api[_.-]?key|access[_.-]?key|auth[_.-]?key|private[_.-]?key|credentials?)[\w.-]*["']?\s*[:=]\s*["']?(?P<secret>[^\s"'` + "`" + `,;#]{8,})`)},
+}
+
+// secretPlaceholderHints indicam valores de exemplo ou lidos do ambiente, que não são segredos
+var secretPlaceholderHints = []string{
+	"change", "replace", "your", "example", "placeholder", "dummy", "sample", "xxx", "todo",
+	"redacted", "<", ">", "${", "{{", "%(", "(", "process.env", "getenv", "environ", "secret_here",
+}
+
+// secretCommonValues são senhas de desenvolvimento que não vale a pena apontar
+var secretCommonValues = map[string]bool{
+	"password": true, "postgres": true, "root": true, "admin": true, "secret": true, "test": true,
+	"pass": true, "mysql": true, "guest": true, "user": true, "dev": true, "development": true,
+}
+
+// SecretFinding é um possível segredo encontrado em um arquivo
+type SecretFinding struct {
+	Path string
+	Line int
+	Rule string
+	// Preview mostra só o início do valor
+	Preview string
+}
+
+// SecretReport é o resultado da verificação de segredos de um manifesto
+type SecretReport struct {
+	Action   string
+	Findings []SecretFinding
+	// EnvExamples mapeia cada arquivo .env com segredos para o .env.example gravado no lugar
+	// dele; vazio quando o .env.example já existia e o .env foi descartado
+	EnvExamples map[string]string
+}
+
+// secretAction é a ação escolhida pela flag --secrets, que prevalece sobre o config.yaml
+var secretAction string
+
+// SetSecretAction define a ação para os segredos encontrados; vazio usa secrets.action do
+// config.yaml ou, na falta dele, warn
+func SetSecretAction(action string) {
+	secretAction = action
+}
+
+// secretScanner aplica as regras ativas, respeitando as listas de valores e caminhos permitidos
+type secretScanner struct {
+	rules      []secretRule
+	allow      []*regexp.Regexp
+	allowPaths []string
+}
+
+// newSecretScanner monta o verificador a partir da seção secrets do config.yaml
+func newSecretScanner(cfg config.SecretsConfig) (*secretScanner, error) {
+	disabled := make(map[string]bool)
+	for _, name := range cfg.DisabledRules {
+		disabled[strings.ToLower(name)] = true
+	}
+	s := &secretScanner{allowPaths: cfg.AllowPaths}
+	for _, rule := range secretRules {
+		if !disabled[rule.name] {
+			s.rules = append(s.rules, rule)
+		}
+	}
+	for _, expr := range cfg.Allow {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("expressão inválida em secrets.allow: %q: %v", expr, err)
+		}
+		s.allow = append(s.allow, re)
+	}
+	return s, nil
+}
+
+// allowedPath indica se o arquivo está em secrets.allow_paths. Padrões sem barra valem
+// para o nome do arquivo em qualquer diretório.
+func (s *secretScanner) allowedPath(rel string) bool {
+	for _, pattern := range s.allowPaths {
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+		if strings.HasSuffix(pattern, "/*") && strings.HasPrefix(rel, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretSpan é o trecho de um valor a ser substituído
+type secretSpan struct {
+	start, end int
+}
+
+// scan procura segredos no conteúdo de um arquivo, retornando os achados e o conteúdo
+// com os valores trocados por SecretPlaceholder
+func (s *secretScanner) scan(rel, content string) ([]SecretFinding, string) {
+	return s.scanExcept(rel, content, "")
+}
+
+// scanExcept funciona como scan, mas ignora os valores que já aparecem em previous, a
+// versão anterior do arquivo
+func (s *secretScanner) scanExcept(rel, content, previous string) ([]SecretFinding, string) {
+	configFile := isConfigFile(rel)
+	var findings []SecretFinding
+	var spans []secretSpan
+	for _, rule := range s.rules {
+		if rule.configOnly && !configFile {
+			continue
+		}
+		group := rule.pattern.SubexpIndex("secret")
+		for _, match := range rule.pattern.FindAllStringSubmatchIndex(content, -1) {
+			start, end := match[0], match[1]
+			if group > 0 && match[2*group] >= 0 {
+				start, end = match[2*group], match[2*group+1]
+			}
+			value := content[start:end]
+			if s.allowed(value) || overlaps(spans, start, end) || (previous != "" && strings.Contains(previous, value)) {
+				continue
+			}
+			// Chaves privadas são apontadas sempre; os demais valores, se não forem de
+			// exemplo e tiverem a entropia mínima da regra
+			if rule.name != "private-key" && (isSecretPlaceholder(value) || shannonEntropy(value) < rule.minEntropy) {
+				continue
+			}
+			spans = append(spans, secretSpan{start, end})
+			findings = append(findings, SecretFinding{
+				Path:    rel,
+				Line:    strings.Count(content[:start], "\n") + 1,
+				Rule:    rule.name,
+				Preview: secretPreview(value),
+			})
+		}
+	}
+	if len(spans) == 0 {
+		return nil, content
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		b.WriteString(content[last:span.start])
+		b.WriteString(SecretPlaceholder)
+		last = span.end
+	}
+	b.WriteString(content[last:])
+	return findings, b.String()
+}
+
+// allowed indica se o valor está em secrets.allow
+func (s *secretScanner) allowed(value string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlaps(spans []secretSpan, start, end int) bool {
+	for _, span := range spans {
+		if start < span.end && span.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecretPlaceholder indica se o valor é de exemplo, lido do ambiente ou trivial
+func isSecretPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	if lower == strings.ToLower(SecretPlaceholder) || secretCommonValues[lower] {
+		return true
+	}
+	for _, hint := range secretPlaceholderHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	if strings.Trim(lower, "0123456789") == "" || lower == "true" || lower == "false" {
+		return true
+	}
+	return strings.Trim(lower, lower[:1]) == ""
+}
+
+// shannonEntropy calcula a entropia do valor em bits por caractere
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	var entropy float64
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// secretPreview mostra os primeiros caracteres do valor, sem revelá-lo
+func secretPreview(value string) string {
+	runes := []rune(strings.SplitN(value, "\n", 2)[0])
+	if len(runes) > 4 {
+		runes = runes[:4]
+	}
+	return string(runes) + "…"
+}
+
+// configFileExtensions são as extensões dos arquivos de configuração verificados pela
+// regra genérica de atribuições como password = "..."
+var configFileExtensions = map[string]bool{
+	".json": true, ".yaml": true, ".yml": true, ".toml": true, ".ini": true, ".cfg": true,
+	".conf": true, ".properties": true, ".env": true, ".xml": true, ".tfvars": true,
+}
+
+// isConfigFile indica se o arquivo é de configuração ou um .env
+func isConfigFile(rel string) bool {
+	base := path.Base(rel)
+	return strings.HasPrefix(base, ".env") || configFileExtensions[strings.ToLower(path.Ext(base))]
+}
+
+// isEnvFile indica se o arquivo é um .env com valores reais (.env, .env.local...), e não
+// um modelo como .env.example
+func isEnvFile(rel string) bool {
+	base := path.Base(rel)
+	if base != ".env" && !strings.HasPrefix(base, ".env.") {
+		return false
+	}
+	for _, suffix := range []string{".example", ".sample", ".template", ".dist"} {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// CurrentSecretAction retorna a ação para os segredos encontrados, da flag --secrets ou
+// do config.yaml
+func CurrentSecretAction() (string, error) {
+	return resolveSecretAction(config.LoadConfig().Secrets)
+}
+
+// resolveSecretAction retorna a ação da flag, do config.yaml ou a padrão, conferindo o nome
+func resolveSecretAction(cfg config.SecretsConfig) (string, error) {
+	action := secretAction
+	if action == "" {
+		action = cfg.Action
+	}
+	if action == "" {
+		return SecretWarn, nil
+	}
+	for _, a := range SecretActions {
+		if strings.EqualFold(action, a) {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("ação de segredos desconhecida: %s (%s)", action, strings.Join(SecretActions, ", "))
+}
+
+// ScanSecrets procura credenciais nos arquivos de texto do manifesto antes da gravação e
+// aplica a ação configurada: warn aponta, redact troca os valores por SecretPlaceholder e
+// abort retorna ErrSecretsFound. Exceto com abort, um .env com segredos é gravado como
+// .env.example, sempre com os valores trocados.
+func ScanSecrets(m *ProjectManifest) (*SecretReport, error) {
+	cfg := config.LoadConfig().Secrets
+	action, err := resolveSecretAction(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scanner, err := newSecretScanner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SecretReport{Action: action, EnvExamples: make(map[string]string)}
+	existing := make(map[string]bool, len(m.Files))
+	for _, file := range m.Files {
+		existing[file.Path] = true
+	}
+	files := m.Files[:0]
+	for _, file := range m.Files {
+		if !file.Resolved() || file.Type == ContentBase64 || scanner.allowedPath(file.Path) {
+			files = append(files, file)
+			continue
+		}
+		findings, redacted := scanner.scan(file.Path, file.Content)
+		if len(findings) == 0 {
+			files = append(files, file)
+			continue
+		}
+		report.Findings = append(report.Findings, findings...)
+
+		env := isEnvFile(file.Path)
+		if action == SecretRedact || (env && action == SecretWarn) {
+			file.Content = redacted
+			file.SHA256 = contentChecksum(redacted)
+		}
+		if env && action != SecretAbort {
+			example := envExampleFor(file.Path)
+			if existing[example] {
+				// O .env.example gerado já documenta as variáveis
+				report.EnvExamples[file.Path] = ""
+				continue
+			}
+			report.EnvExamples[file.Path] = example
+			existing[example] = true
+			file.Path = example
+		}
+		files = append(files, file)
+	}
+	m.Files = files
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	if action == SecretAbort && len(report.Findings) > 0 {
+		return report, ErrSecretsFound
+	}
+	return report, nil
+}
+
+// envExampleFor retorna o .env.example gravado no lugar de um .env com segredos
+func envExampleFor(rel string) string {
+	if path.Base(rel) != ".env" {
+		return path.Join(path.Dir(rel), ".env.example")
+	}
+	return rel + ".example"
+}
+
+// ScanChanges verifica os segredos dos arquivos adicionados e modificados antes da gravação,
+// com a mesma ação de ScanSecrets. Valores que já estavam no arquivo não são apontados, para
+// não alterar segredos do próprio projeto. Exceto com abort, um .env com segredos é gravado
+// como .env.example ou, se ele já existir, descartado.
+func ScanChanges(dir string, changes []FileChange) ([]FileChange, *SecretReport, error) {
+	cfg := config.LoadConfig().Secrets
+	action, err := resolveSecretAction(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	scanner, err := newSecretScanner(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &SecretReport{Action: action, EnvExamples: make(map[string]string)}
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		changed[change.Path] = true
+	}
+	var result []FileChange
+	for _, change := range changes {
+		if change.Kind == ChangeRemoved || !utf8.ValidString(change.NewContent) || scanner.allowedPath(change.Path) {
+			result = append(result, change)
+			continue
+		}
+		findings, redacted := scanner.scanExcept(change.Path, change.NewContent, change.OldContent)
+		if len(findings) == 0 {
+			result = append(result, change)
+			continue
+		}
+		report.Findings = append(report.Findings, findings...)
+
+		env := isEnvFile(change.Path)
+		if action == SecretRedact || (env && action == SecretWarn) {
+			change.NewContent = redacted
+		}
+		if env && action != SecretAbort {
+			example := envExampleFor(change.Path)
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(example))); err == nil || changed[example] {
+				report.EnvExamples[change.Path] = ""
+				continue
+			}
+			report.EnvExamples[change.Path] = example
+			changed[example] = true
+			change.Path, change.Kind, change.OldContent = example, ChangeAdded, ""
+		}
+		result = append(result, change)
+	}
+
+	if action == SecretAbort && len(report.Findings) > 0 {
+		return nil, report, ErrSecretsFound
+	}
+	return result, report, nil
+}
+
+// rawResponseFile é o nome com que a resposta bruta aparece no relatório de segredos
+const rawResponseFile = "zion_response.json"
+
+// ScanRawResponse verifica os segredos da resposta bruta do modelo, gravada quando o
+// manifesto não pôde ser criado, e retorna o texto a gravar: com redact, os valores são
+// trocados por SecretPlaceholder; com abort, retorna ErrSecretsFound. Um erro na seção
+// secrets do config.yaml também impede a gravação.
+func ScanRawResponse(response string) (string, *SecretReport, error) {
+	cfg := config.LoadConfig().Secrets
+	action, err := resolveSecretAction(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	scanner, err := newSecretScanner(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	report := &SecretReport{Action: action, EnvExamples: make(map[string]string)}
+	findings, redacted := scanner.scan(rawResponseFile, response)
+	report.Findings = findings
+	switch {
+	case len(findings) == 0:
+		return response, report, nil
+	case action == SecretAbort:
+		return "", report, ErrSecretsFound
+	case action == SecretRedact:
+		return redacted, report, nil
+	}
+	return response, report, nil
+}
+
+// fileHasSecrets indica se um arquivo recebido durante o streaming tem possíveis segredos;
+// esses arquivos só são gravados com o projeto completo, depois da verificação
+func fileHasSecrets(file ManifestFile) bool {
+	if !file.Resolved() || file.Type == ContentBase64 {
+		return false
+	}
+	cfg := config.LoadConfig().Secrets
+	scanner, err := newSecretScanner(cfg)
+	if err != nil {
+		return true
+	}
+	if scanner.allowedPath(file.Path) {
+		return false
+	}
+	findings, _ := scanner.scan(file.Path, file.Content)
+	return len(findings) > 0
+}
+
+// checkSecrets verifica os segredos do manifesto e mostra o resultado
+func checkSecrets(m *ProjectManifest) error {
+	report, err := ScanSecrets(m)
+	if report != nil {
+		printSecretReport(report)
+	}
+	return err
+}
+
+// printSecretReport mostra os segredos encontrados e o que foi feito com eles
+func printSecretReport(report *SecretReport) {
+	if len(report.Findings) == 0 {
+		return
+	}
+	fmt.Print(i18n.T("secrets.found", len(report.Findings)))
+	for _, finding := range report.Findings {
+		fmt.Printf("   ⚠️  %s:%d: %s (%s)\n", finding.Path, finding.Line, finding.Rule, finding.Preview)
+	}
+	switch report.Action {
+	case SecretRedact:
+		fmt.Print(i18n.T("secrets.redacted", SecretPlaceholder))
+	case SecretWarn:
+		fmt.Print(i18n.T("secrets.kept"))
+	case SecretAbort:
+		fmt.Print(i18n.T("secrets.aborted"))
+	}
+	var envFiles []string
+	for env := range report.EnvExamples {
+		envFiles = append(envFiles, env)
+	}
+	sort.Strings(envFiles)
+	for _, env := range envFiles {
+		if example := report.EnvExamples[env]; example != "" {
+			fmt.Print(i18n.T("secrets.env_example", env, example, SecretPlaceholder))
+		} else {
+			fmt.Print(i18n.T("secrets.env_dropped", env))
+		}
+	}
+}
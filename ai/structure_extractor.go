@@ -53,9 +53,7 @@ func ExtractProjectStructure(response string) ([]string, map[string]string) {
 				fileContent := match[2]
 				
 				// Desescapa aspas no conteúdo
-				fileContent = strings.ReplaceAll(fileContent, "\\\"", "\"")
-				fileContent = strings.ReplaceAll(fileContent, "\\n", "\n")
-				fileContent = strings.ReplaceAll(fileContent, "\\t", "\t")
+				fileContent = ProcessEscapedChars(fileContent)
 				
 				files[fileName] = fileContent
 				fmt.Printf("Arquivo encontrado: %s (%d bytes)\n", fileName, len(fileContent))
@@ -75,9 +73,7 @@ func ExtractProjectStructure(response string) ([]string, map[string]string) {
 				fileContent := match[2]
 				
 				// Desescapa aspas no conteúdo
-				fileContent = strings.ReplaceAll(fileContent, "\\\"", "\"")
-				fileContent = strings.ReplaceAll(fileContent, "\\n", "\n")
-				fileContent = strings.ReplaceAll(fileContent, "\\t", "\t")
+				fileContent = ProcessEscapedChars(fileContent)
 				
 				files[fileName] = fileContent
 				fmt.Printf("Arquivo encontrado (formato alt): %s (%d bytes)\n", fileName, len(fileContent))
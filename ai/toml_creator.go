@@ -5,8 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
-	"time"
 )
 
 // ExtractAndCreateTomlProject extrai diretamente os diretórios e arquivos do JSON
@@ -26,6 +26,8 @@ func ExtractAndCreateTomlProject(projectName string, jsonStr string) error {
 	// Coletar diretórios e arquivos
 	directories := []string{}
 	fileContents := make(map[string]string)
+	// Arquivos recebidos como string, para o fallback de respostas escapadas duas vezes
+	texts := make(map[string]string)
 
 	// Extrair diretórios usando regex
 	dirRegex := regexp.MustCompile(`"directories"\s*:\s*\[\s*((?:"[^"]+"\s*,?\s*)*)\]`)
@@ -96,8 +98,6 @@ func ExtractAndCreateTomlProject(projectName string, jsonStr string) error {
 			// Extrair o conteúdo do objeto
 			content := jsonStr[objStart-1:objEnd+1] // Incluir as chaves
 			
-			// Criar o arquivo
-			CreateFile(projectName, fileName, content)
 			fileContents[fileName] = content
 			continue
 		}
@@ -116,14 +116,9 @@ func ExtractAndCreateTomlProject(projectName string, jsonStr string) error {
 		// Extrair o conteúdo
 		content := jsonStr[contentStart:contentEnd]
 		
-		// Processar caracteres escapados apenas para arquivos que não são JSON
-		if !isJSONFile(fileName) {
-			content = ProcessEscapedChars(content)
-		} else {
-			// Para arquivos JSON, apenas remover escapes desnecessários
-			content = PreserveJSONFormat(content)
-			fmt.Printf("Preservado formato original do arquivo JSON: %s\n", fileName)
-		}
+		// Decodificar os escapes da string JSON uma única vez, inclusive em arquivos JSON
+		content = ProcessEscapedChars(content)
+		texts[fileName] = content
 		
 		// Determinar onde começa o próximo arquivo ou o final da seção "files"
 		var nextPos int
@@ -151,56 +146,39 @@ func ExtractAndCreateTomlProject(projectName string, jsonStr string) error {
 			}
 		}
 		
-		// Criar o arquivo
-		CreateFile(projectName, fileName, content)
 		fileContents[fileName] = content
 	}
+
+	// Os arquivos só são criados depois de decidir, pela resposta inteira, se ela foi
+	// escapada duas vezes
+	for _, fileName := range decodeDoubleEscaped(texts) {
+		fileContents[fileName] = texts[fileName]
+	}
+	fileNames := make([]string, 0, len(fileContents))
+	for fileName := range fileContents {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		CreateFile(projectName, fileName, fileContents[fileName])
+	}
 	
 	// Gerar o arquivo TOML
-	generateTomlFile(projectName, directories, fileContents)
+	if err := generateTomlFile(projectName, directories, fileContents); err != nil {
+		return err
+	}
 	
 	fmt.Println("\nEstrutura do projeto criada com sucesso em:", projectName)
 	return nil
 }
 
-// generateTomlFile gera um arquivo TOML com a estrutura do projeto
+// generateTomlFile grava o manifesto TOML do projeto, com o conteúdo completo dos arquivos
 func generateTomlFile(projectName string, directories []string, files map[string]string) error {
-	// Criar o arquivo TOML
 	tomlPath := filepath.Join(projectName, "project_structure.toml")
-	
-	// Construir o conteúdo TOML
-	var tomlContent strings.Builder
-	
-	// Adicionar cabeçalho
-	tomlContent.WriteString("# Estrutura do projeto gerada pelo Zion\n")
-	tomlContent.WriteString(fmt.Sprintf("# Gerado em: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
-	
-	// Adicionar informações do projeto
-	tomlContent.WriteString("[project]\n")
-	tomlContent.WriteString(fmt.Sprintf("name = \"%s\"\n", projectName))
-	tomlContent.WriteString(fmt.Sprintf("created_at = \"%s\"\n\n", time.Now().Format("2006-01-02 15:04:05")))
-	
-	// Adicionar diretórios
-	tomlContent.WriteString("# Diretórios\n")
-	tomlContent.WriteString("directories = [\n")
-	for _, dir := range directories {
-		tomlContent.WriteString(fmt.Sprintf("  \"%s\",\n", dir))
-	}
-	tomlContent.WriteString("]\n\n")
-	
-	// Adicionar arquivos
-	tomlContent.WriteString("# Arquivos\n")
-	tomlContent.WriteString("[files]\n")
-	for filePath := range files {
-		// Usar o caminho do arquivo como chave e um comentário indicando que o conteúdo está no arquivo
-		tomlContent.WriteString(fmt.Sprintf("\"%s\" = \"<conteúdo no arquivo>\"\n", filePath))
-	}
-	
-	// Escrever o conteúdo no arquivo
-	if err := os.WriteFile(tomlPath, []byte(tomlContent.String()), 0644); err != nil {
+	if err := WriteManifestFile(tomlPath, ManifestTOML, NewProjectManifest(filepath.Base(projectName), directories, files)); err != nil {
 		return fmt.Errorf("erro ao criar arquivo TOML: %v", err)
 	}
-	
+
 	fmt.Println("Arquivo TOML gerado em:", tomlPath)
 	return nil
 }
@@ -0,0 +1,214 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"zion/i18n"
+	"zion/plugins"
+	"zion/prompts"
+)
+
+// finishReasonMaxTokens é o finishReason informado pela API quando a resposta atinge o limite de tokens
+const finishReasonMaxTokens = "MAX_TOKENS"
+
+// maxContinuations limita quantas continuações são pedidas para uma mesma resposta truncada
+const maxContinuations = 3
+
+// continueTruncatedResponse pede continuações enquanto a API informar MAX_TOKENS,
+// concatenando os trechos. contents é a conversa que originou a resposta e onText,
+// se informado, recebe cada trecho novo; fresh indica que o trecho é um manifesto
+// recomeçado do zero, que substitui todo o texto anterior em vez de continuá-lo.
+func continueTruncatedResponse(contents []Content, result *geminiResult, onText func(text string, fresh bool)) (*geminiResult, error) {
+	if result.FinishReason != finishReasonMaxTokens {
+		return result, nil
+	}
+
+	// continuation.tmpl pede ao modelo que retome a resposta interrompida
+	continuationPrompt, err := renderPrompt("continuation.tmpl", prompts.Data{})
+	if err != nil {
+		return nil, err
+	}
+
+	combined := &geminiResult{Text: result.Text, FinishReason: result.FinishReason, Truncated: true}
+	for i := 1; i <= maxContinuations && combined.FinishReason == finishReasonMaxTokens; i++ {
+		fmt.Print(i18n.T("ai.truncated.continue", i, maxContinuations))
+
+		turns := append(append([]Content(nil), contents...),
+			Content{Parts: []Part{{Text: combined.Text}}, Role: "model"},
+			Content{Parts: []Part{{Text: continuationPrompt}}, Role: "user"},
+		)
+		next, err := sendGeminiRequest(buildGeminiContentsRequest(turns))
+		if err != nil {
+			// A resposta parcial ainda pode ser aproveitada pela recuperação por arquivo
+			fmt.Print(i18n.T("ai.truncated.continue_failed", err))
+			break
+		}
+
+		text := stripContinuationFence(next.Text)
+		fresh := looksLikeFreshManifest(text)
+		if fresh {
+			// O modelo recomeçou do zero em vez de continuar: usa a nova resposta inteira
+			combined.Text = text
+		} else {
+			combined.Text += text
+		}
+		combined.FinishReason = next.FinishReason
+		if onText != nil {
+			onText(text, fresh)
+		}
+	}
+
+	return combined, nil
+}
+
+// stripContinuationFence remove um bloco markdown aberto no início de uma continuação
+func stripContinuationFence(text string) string {
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+	for _, fence := range []string{"```json\n", "```\n"} {
+		if strings.HasPrefix(trimmed, fence) {
+			return strings.TrimPrefix(trimmed, fence)
+		}
+	}
+	return text
+}
+
+// looksLikeFreshManifest indica se o texto é o início de um manifesto completo
+func looksLikeFreshManifest(text string) bool {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	head := trimmed
+	if len(head) > 40 {
+		head = head[:40]
+	}
+	return strings.Contains(head, `"structure"`)
+}
+
+// recoverTruncatedManifest aproveita os arquivos completos de uma resposta truncada
+// e regenera individualmente o arquivo interrompido e os que ainda não tinham sido enviados
+func recoverTruncatedManifest(language, framework, projectName, description, partial string) (string, error) {
+	parser := NewManifestStreamParser(nil)
+	parser.Feed(partial)
+
+	complete := parser.Files()
+	pending := parser.PendingFile()
+	fmt.Print(i18n.T("ai.truncated.recovering", len(complete)))
+	if pending != "" {
+		fmt.Print(i18n.T("ai.truncated.pending", pending))
+	}
+	fmt.Println()
+
+	projectDesc, err := buildProjectDescription(language, framework, projectName, description)
+	if err != nil {
+		return "", err
+	}
+	remaining, err := requestRemainingOutline(projectDesc, complete, pending)
+	if err != nil {
+		return "", fmt.Errorf("erro ao listar arquivos faltantes: %v", err)
+	}
+
+	// A árvore enviada como contexto inclui os arquivos que já estão prontos
+	full := &ProjectOutline{Directories: append(parser.Directories(), remaining.Directories...)}
+	for _, file := range complete {
+		full.Files = append(full.Files, OutlineFile{Path: file.Path, Purpose: "já gerado"})
+	}
+	full.Files = append(full.Files, remaining.Files...)
+
+	hookCtx := plugins.ScaffoldContext{
+		ProjectName: projectName,
+		Language:    language,
+		Description: description,
+	}
+	contents, failed, err := generateOutlineFiles(hookCtx, projectDesc, FormatOutline(full), remaining.Files, DefaultOutlineWorkers, func(path string, err error) {
+		if err == nil {
+			fmt.Printf("   🩹 %s\n", path)
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	// Os arquivos completos da resposta original são gravados mesmo que nenhum faltante seja gerado
+	reportOutlineFailures(failed)
+
+	var scaffoldResp ScaffoldResponse
+	scaffoldResp.Structure.Directories = uniqueStrings(full.Directories)
+	scaffoldResp.Structure.Files = make(map[string]interface{})
+	for _, file := range complete {
+		scaffoldResp.Structure.Files[file.Path] = map[string]interface{}{"content": file.Content}
+	}
+	recovered := make([]string, 0, len(contents))
+	for path, content := range contents {
+		scaffoldResp.Structure.Files[path] = map[string]interface{}{"content": content}
+		recovered = append(recovered, path)
+	}
+	sort.Strings(recovered)
+	fmt.Print(i18n.T("ai.truncated.recovered", len(recovered), strings.Join(recovered, ", ")))
+
+	data, err := json.Marshal(scaffoldResp)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gerar JSON final: %v", err)
+	}
+	return string(data), nil
+}
+
+// requestRemainingOutline pergunta ao modelo quais arquivos ainda faltam no projeto
+func requestRemainingOutline(projectDesc string, complete []StreamedFile, pending string) (*ProjectOutline, error) {
+	data := prompts.Data{ProjectDescription: projectDesc}
+	for _, file := range complete {
+		data.CompletedFiles = append(data.CompletedFiles, file.Path)
+	}
+	prompt, err := renderPrompt("remaining_outline.tmpl", data)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := callGeminiAPI(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var outline ProjectOutline
+	if err := json.Unmarshal([]byte(response), &outline); err != nil {
+		return nil, fmt.Errorf("erro ao processar lista de arquivos faltantes: %v", err)
+	}
+
+	// Garante que o arquivo interrompido seja regenerado e que nenhum completo seja refeito
+	completed := make(map[string]bool)
+	for _, file := range complete {
+		completed[file.Path] = true
+	}
+	var files []OutlineFile
+	hasPending := pending == ""
+	for _, file := range outline.Files {
+		if completed[file.Path] {
+			continue
+		}
+		if file.Path == pending {
+			hasPending = true
+		}
+		files = append(files, file)
+	}
+	if !hasPending {
+		files = append([]OutlineFile{{Path: pending, Purpose: "arquivo interrompido na resposta original"}}, files...)
+	}
+	outline.Files = files
+
+	return &outline, nil
+}
+
+// uniqueStrings remove itens repetidos preservando a ordem
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range items {
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
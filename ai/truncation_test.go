@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"zion/config"
+	"zion/prompts"
+)
+
+// seedContinuations grava no cache as respostas das continuações, na ordem em que
+// continueTruncatedResponse as pede, para que o teste não chame a API
+func seedContinuations(t *testing.T, contents []Content, first string, replies []*geminiResult) {
+	t.Helper()
+	continuationPrompt, err := renderPrompt("continuation.tmpl", prompts.Data{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.LoadConfig()
+	text := first
+	for _, reply := range replies {
+		turns := append(append([]Content(nil), contents...),
+			Content{Parts: []Part{{Text: text}}, Role: "model"},
+			Content{Parts: []Part{{Text: continuationPrompt}}, Role: "user"},
+		)
+		storeCachedResult(cfg, buildGeminiContentsRequest(turns), reply)
+		next := stripContinuationFence(reply.Text)
+		if looksLikeFreshManifest(next) {
+			text = next
+		} else {
+			text += next
+		}
+	}
+}
+
+func TestContinueTruncatedResponse(t *testing.T) {
+	type chunk struct {
+		text  string
+		fresh bool
+	}
+	tests := []struct {
+		name    string
+		first   *geminiResult
+		replies []*geminiResult
+		text    string
+		reason  string
+		chunks  []chunk
+	}{
+		{
+			name:   "resposta completa",
+			first:  &geminiResult{Text: `{"structure":{}}`, FinishReason: "STOP"},
+			text:   `{"structure":{}}`,
+			reason: "STOP",
+		},
+		{
+			name:  "continuações concatenadas",
+			first: &geminiResult{Text: `{"structure":{"files":{"a.txt":"a`, FinishReason: finishReasonMaxTokens},
+			replies: []*geminiResult{
+				{Text: `bc","b.txt":`, FinishReason: finishReasonMaxTokens},
+				{Text: "```json\n" + `"b"}}}`, FinishReason: "STOP"},
+			},
+			text:   `{"structure":{"files":{"a.txt":"abc","b.txt":"b"}}}`,
+			reason: "STOP",
+			chunks: []chunk{{`bc","b.txt":`, false}, {`"b"}}}`, false}},
+		},
+		{
+			name:  "manifesto recomeçado do zero",
+			first: &geminiResult{Text: `{"structure":{"files":{"a.txt":"a`, FinishReason: finishReasonMaxTokens},
+			replies: []*geminiResult{
+				{Text: `{"structure":{"files":{"a.txt":"novo"}}}`, FinishReason: "STOP"},
+			},
+			text:   `{"structure":{"files":{"a.txt":"novo"}}}`,
+			reason: "STOP",
+			chunks: []chunk{{`{"structure":{"files":{"a.txt":"novo"}}}`, true}},
+		},
+		{
+			name:  "limite de continuações",
+			first: &geminiResult{Text: "a", FinishReason: finishReasonMaxTokens},
+			replies: []*geminiResult{
+				{Text: "b", FinishReason: finishReasonMaxTokens},
+				{Text: "c", FinishReason: finishReasonMaxTokens},
+				{Text: "d", FinishReason: finishReasonMaxTokens},
+				{Text: "e", FinishReason: "STOP"},
+			},
+			text:   "abcd",
+			reason: finishReasonMaxTokens,
+			chunks: []chunk{{"b", false}, {"c", false}, {"d", false}},
+		},
+		{
+			name:   "falha na continuação mantém a resposta parcial",
+			first:  &geminiResult{Text: `{"structure":{"files":{"a.txt":"a`, FinishReason: finishReasonMaxTokens},
+			text:   `{"structure":{"files":{"a.txt":"a`,
+			reason: finishReasonMaxTokens,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Cache isolado e sem chave da API: uma continuação fora do cache falha
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("GEMINI_API_KEY", "")
+			t.Setenv("ZION_CACHE_TTL", "")
+			contents := userContents("gere o projeto")
+			seedContinuations(t, contents, tt.first.Text, tt.replies)
+
+			var chunks []chunk
+			result, err := continueTruncatedResponse(contents, tt.first, func(text string, fresh bool) {
+				chunks = append(chunks, chunk{text, fresh})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Text != tt.text {
+				t.Errorf("texto = %q, esperado %q", result.Text, tt.text)
+			}
+			if result.FinishReason != tt.reason {
+				t.Errorf("finishReason = %q, esperado %q", result.FinishReason, tt.reason)
+			}
+			if truncated := tt.first.FinishReason == finishReasonMaxTokens; result.Truncated != truncated {
+				t.Errorf("Truncated = %v, esperado %v", result.Truncated, truncated)
+			}
+			if !reflect.DeepEqual(chunks, tt.chunks) {
+				t.Errorf("trechos = %+v, esperado %+v", chunks, tt.chunks)
+			}
+		})
+	}
+}
+
+func TestStripContinuationFence(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"abc", "abc"},
+		{"```json\n\"a\"}", "\"a\"}"},
+		{"\n  ```\n}", "}"},
+		{"texto ```json\n", "texto ```json\n"},
+	}
+	for _, tt := range tests {
+		if got := stripContinuationFence(tt.input); got != tt.want {
+			t.Errorf("stripContinuationFence(%q) = %q, esperado %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLooksLikeFreshManifest(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{`{"structure":{"files":{}}}`, true},
+		{"  {\n  \"structure\": {", true},
+		{`"b.txt":"b"}}}`, false},
+		{`{"name":"x","description":"` + strings.Repeat("a", 40) + `","structure":{}}`, false},
+	}
+	for _, tt := range tests {
+		if got := looksLikeFreshManifest(tt.input); got != tt.want {
+			t.Errorf("looksLikeFreshManifest(%q) = %v, esperado %v", tt.input, got, tt.want)
+		}
+	}
+}
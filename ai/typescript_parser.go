@@ -158,13 +158,9 @@ func extractFilesWithAtSymbol(jsonContent string) map[string]string {
 			packageJsonContent := packageJsonMatches[1]
 			
 			// Desescapar o conteúdo
-			packageJsonContent = strings.ReplaceAll(packageJsonContent, "\\\"", "\"")
-			packageJsonContent = strings.ReplaceAll(packageJsonContent, "\\n", "\n")
-			packageJsonContent = strings.ReplaceAll(packageJsonContent, "\\t", "\t")
-			packageJsonContent = strings.ReplaceAll(packageJsonContent, "\\\\", "\\")
+			packageJsonContent = ProcessEscapedChars(packageJsonContent)
 			
-			// Corrigir o problema com @types
-			packageJsonContent = fixPackageJsonContent(packageJsonContent)
+			packageJsonContent = normalizePackageJSON(packageJsonContent)
 			
 			files["package.json"] = packageJsonContent
 			fmt.Println("Arquivo package.json processado com sucesso")
@@ -180,10 +176,7 @@ func extractFilesWithAtSymbol(jsonContent string) map[string]string {
 				fileContent := match[2]
 				
 				// Desescapar o conteúdo
-				fileContent = strings.ReplaceAll(fileContent, "\\\"", "\"")
-				fileContent = strings.ReplaceAll(fileContent, "\\n", "\n")
-				fileContent = strings.ReplaceAll(fileContent, "\\t", "\t")
-				fileContent = strings.ReplaceAll(fileContent, "\\\\", "\\")
+				fileContent = ProcessEscapedChars(fileContent)
 				
 				files[fileName] = fileContent
 				fmt.Printf("Arquivo encontrado: %s\n", fileName)
@@ -199,10 +192,7 @@ func extractFilesWithAtSymbol(jsonContent string) map[string]string {
 			fileContent := lastFileMatches[2]
 			
 			// Desescapar o conteúdo
-			fileContent = strings.ReplaceAll(fileContent, "\\\"", "\"")
-			fileContent = strings.ReplaceAll(fileContent, "\\n", "\n")
-			fileContent = strings.ReplaceAll(fileContent, "\\t", "\t")
-			fileContent = strings.ReplaceAll(fileContent, "\\\\", "\\")
+			fileContent = ProcessEscapedChars(fileContent)
 			
 			files[fileName] = fileContent
 			fmt.Printf("Arquivo encontrado (último): %s\n", fileName)
@@ -233,14 +223,10 @@ func extractFilesAlternative(jsonContent string) map[string]string {
 			fileContent := fileMatches[2]
 			
 			// Desescapar o conteúdo
-			fileContent = strings.ReplaceAll(fileContent, "\\\"", "\"")
-			fileContent = strings.ReplaceAll(fileContent, "\\n", "\n")
-			fileContent = strings.ReplaceAll(fileContent, "\\t", "\t")
-			fileContent = strings.ReplaceAll(fileContent, "\\\\", "\\")
+			fileContent = ProcessEscapedChars(fileContent)
 			
-			// Corrigir o problema com @types em package.json
 			if fileName == "package.json" {
-				fileContent = fixPackageJsonContent(fileContent)
+				fileContent = normalizePackageJSON(fileContent)
 			}
 			
 			files[fileName] = fileContent
@@ -327,13 +313,3 @@ app.listen(port, () => {
 	
 	return files
 }
-
-// fixPackageJsonContent corrige o conteúdo do package.json para lidar com o problema do @
-func fixPackageJsonContent(content string) string {
-	// Corrigir o problema com @types
-	// Busca por linhas como: "@types/express": "^4.17.17",
-	atTypesRegex := regexp.MustCompile(`"(@[^"]+)":\s*"([^"]+)"`)
-	fixed := atTypesRegex.ReplaceAllString(content, "\"$1\": \"$2\"")
-	
-	return fixed
-}
@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// baseManifestFile guarda, dentro do projeto, o conteúdo gerado originalmente pelo modelo,
+// usado como base do merge de três vias em zion update
+const baseManifestFile = ".zion/base.json"
+
+// SaveBaseManifest grava o manifesto gerado pelo modelo como base das próximas atualizações
+func SaveBaseManifest(dir string, m *ProjectManifest) error {
+	if err := WriteManifestFile(filepath.Join(dir, baseManifestFile), ManifestJSON, m); err != nil {
+		return fmt.Errorf("erro ao salvar o conteúdo gerado: %v", err)
+	}
+	return nil
+}
+
+// LoadBaseManifest lê o conteúdo gerado originalmente para o projeto
+func LoadBaseManifest(dir string) (*ProjectManifest, error) {
+	path := filepath.Join(dir, baseManifestFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("o projeto não tem o registro do conteúdo gerado (%s); ele é criado pelo zion scaffold", baseManifestFile)
+	}
+	return ReadManifestFile(path, ManifestJSON)
+}
+
+// WriteManifestTree grava em dir os diretórios e os arquivos resolvidos do manifesto, sem
+// mensagens nem a verificação de segredos já feita na geração
+func WriteManifestTree(dir string, m *ProjectManifest) error {
+	for _, d := range m.Directories {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.FromSlash(d)), 0755); err != nil {
+			return fmt.Errorf("erro ao criar diretório '%s': %v", d, err)
+		}
+	}
+	for _, file := range m.Files {
+		if !file.Resolved() {
+			continue
+		}
+		if err := writeManifestFile(dir, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshManifest relê de dir os arquivos do manifesto, alterados pelas etapas pós-criação,
+// e acrescenta os arquivos criados por elas, como o .gitignore. Arquivos que a leitura do
+// projeto ignora (grandes ou no .gitignore) ficam como estavam no manifesto.
+func RefreshManifest(dir string, m *ProjectManifest) (*ProjectManifest, error) {
+	disk, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	onDisk := make(map[string]ManifestFile, len(disk.Files))
+	for _, file := range disk.Files {
+		onDisk[file.Path] = file
+	}
+
+	refreshed := *m
+	refreshed.Files = nil
+	known := make(map[string]bool, len(m.Files))
+	for _, file := range m.Files {
+		known[file.Path] = true
+		if current, ok := onDisk[file.Path]; ok {
+			file = current
+		}
+		refreshed.Files = append(refreshed.Files, file)
+	}
+	for _, file := range disk.Files {
+		if !known[file.Path] {
+			refreshed.Files = append(refreshed.Files, file)
+		}
+	}
+	sort.Slice(refreshed.Files, func(i, j int) bool { return refreshed.Files[i].Path < refreshed.Files[j].Path })
+	return &refreshed, nil
+}
+
+// RegenerateOptions controla a nova geração de um projeto a partir do lock
+type RegenerateOptions struct {
+	// Plugins são os plugins registrados, que participam da geração como no scaffold
+	Plugins []string
+	// ContextTokens é o orçamento do projeto de referência, quando o lock registra um
+	ContextTokens int
+	// Workers é o número de requisições simultâneas no modo multi-etapas
+	Workers int
+	// OnPrompt recebe o prompt efetivo da geração em etapa única
+	OnPrompt func(prompt string)
+	// OnOutline recebe a árvore planejada no modo multi-etapas
+	OnOutline func(outline *ProjectOutline)
+}
+
+// RegenerateScaffold gera o projeto de novo com as entradas registradas no lock e o
+// prompt e os pacotes de linguagem atuais, retornando o novo manifesto
+func RegenerateScaffold(lock *ScaffoldLock, opts RegenerateOptions) (*ProjectManifest, error) {
+	in := lock.Inputs
+	if in.DocLanguage != "" {
+		previous := docLanguage
+		SetDocLanguage(in.DocLanguage)
+		defer SetDocLanguage(previous)
+	}
+
+	var response string
+	var err error
+	if in.MultiStep {
+		outline, err := GenerateProjectOutline(in.Language, in.Framework, in.Name, in.Description)
+		if err != nil {
+			return nil, err
+		}
+		if opts.OnOutline != nil {
+			opts.OnOutline(outline)
+		}
+		workers := opts.Workers
+		if workers <= 0 {
+			workers = DefaultOutlineWorkers
+		}
+		response, err = GenerateFilesFromOutline(in.Language, in.Framework, in.Name, in.Description, outline, workers, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		scaffoldOpts := ScaffoldOptions{Framework: in.Framework, OnPrompt: opts.OnPrompt}
+		if in.Context != "" {
+			if info, statErr := os.Stat(in.Context); statErr == nil && info.IsDir() {
+				if scaffoldOpts.Context, err = CollectProjectContext(in.Context, opts.ContextTokens); err != nil {
+					return nil, err
+				}
+			}
+		}
+		response, err = GenerateProjectScaffoldingWithOptions(in.Language, in.Name, in.Description, append([]string(nil), opts.Plugins...), scaffoldOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := ManifestFromResponse(in.Name, response)
+	if err != nil {
+		return nil, err
+	}
+	for _, problem := range manifest.Resolve(TemplateDirs()) {
+		fmt.Printf("⚠️  %v\n", problem)
+	}
+	if err := checkSecrets(manifest); err != nil {
+		return nil, err
+	}
+	manifest.Project.Language = in.Language
+	manifest.Project.Framework = in.Framework
+	manifest.Project.Description = in.Description
+	return manifest, nil
+}
+
+// OutlineHash retorna a soma da árvore planejada, no formato registrado no lock
+func OutlineHash(outline *ProjectOutline) string {
+	data, err := json.Marshal(outline)
+	if err != nil {
+		return ""
+	}
+	return HashText(string(data))
+}
@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+	"zion/config"
+	"zion/i18n"
+)
+
+// UsageMetadata é o consumo de tokens informado pela API Gemini
+type UsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// UsageRecord é uma linha do log de uso local, gravada a cada chamada à API
+type UsageRecord struct {
+	Time           time.Time `json:"time"`
+	Profile        string    `json:"profile"`
+	Model          string    `json:"model"`
+	PromptTokens   int       `json:"prompt_tokens"`
+	ResponseTokens int       `json:"response_tokens"`
+	TotalTokens    int       `json:"total_tokens"`
+	Cost           float64   `json:"cost"`
+}
+
+// UsageSummary acumula o consumo de várias chamadas
+type UsageSummary struct {
+	Calls          int
+	PromptTokens   int
+	ResponseTokens int
+	TotalTokens    int
+	Cost           float64
+	Models         []string
+}
+
+// UsageAggregate é o consumo agrupado por dia, perfil e modelo
+type UsageAggregate struct {
+	Day     string
+	Profile string
+	Model   string
+	UsageSummary
+}
+
+var (
+	usageMu      sync.Mutex
+	sessionUsage UsageSummary
+)
+
+// usageLogFile é o nome do log de uso dentro do diretório home do Zion
+const usageLogFile = "usage.jsonl"
+
+// EstimateCost calcula o custo estimado de uma chamada a partir da tabela de preços
+func EstimateCost(prices map[string]config.ModelPrice, model string, promptTokens, responseTokens int) float64 {
+	price, ok := prices[model]
+	if !ok {
+		return 0
+	}
+	return float64(promptTokens)/1e6*price.InputPerMillion + float64(responseTokens)/1e6*price.OutputPerMillion
+}
+
+// recordUsage contabiliza uma chamada na sessão atual e a grava no log de uso
+func recordUsage(cfg *config.Config, meta UsageMetadata) {
+	if meta.TotalTokenCount == 0 && meta.PromptTokenCount == 0 && meta.CandidatesTokenCount == 0 {
+		return
+	}
+
+	record := UsageRecord{
+		Time:           time.Now(),
+		Profile:        cfg.Profile,
+		Model:          cfg.Model,
+		PromptTokens:   meta.PromptTokenCount,
+		ResponseTokens: meta.CandidatesTokenCount,
+		TotalTokens:    meta.TotalTokenCount,
+	}
+	record.Cost = EstimateCost(cfg.Prices, cfg.Model, record.PromptTokens, record.ResponseTokens)
+
+	usageMu.Lock()
+	defer usageMu.Unlock()
+
+	sessionUsage.add(record)
+
+	if err := appendUsageLog(cfg, record); err != nil {
+		fmt.Print(i18n.T("ai.usage.write_failed", err))
+	}
+}
+
+// add soma um registro ao resumo
+func (s *UsageSummary) add(record UsageRecord) {
+	s.Calls++
+	s.PromptTokens += record.PromptTokens
+	s.ResponseTokens += record.ResponseTokens
+	s.TotalTokens += record.TotalTokens
+	s.Cost += record.Cost
+	for _, model := range s.Models {
+		if model == record.Model {
+			return
+		}
+	}
+	s.Models = append(s.Models, record.Model)
+}
+
+// appendUsageLog acrescenta um registro ao log de uso local
+func appendUsageLog(cfg *config.Config, record UsageRecord) error {
+	f, err := os.OpenFile(filepath.Join(cfg.HomeDir, usageLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// SessionUsage retorna o consumo acumulado desde o início do processo
+func SessionUsage() UsageSummary {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	summary := sessionUsage
+	summary.Models = append([]string(nil), sessionUsage.Models...)
+	return summary
+}
+
+// LoadUsageLog lê todos os registros do log de uso local
+func LoadUsageLog() ([]UsageRecord, error) {
+	cfg := config.LoadConfig()
+	f, err := os.Open(filepath.Join(cfg.HomeDir, usageLogFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir log de uso: %v", err)
+	}
+	defer f.Close()
+
+	var records []UsageRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record UsageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Linhas corrompidas são ignoradas para não invalidar o histórico inteiro
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler log de uso: %v", err)
+	}
+	return records, nil
+}
+
+// AggregateUsage agrupa os registros por dia, perfil e modelo, em ordem cronológica
+func AggregateUsage(records []UsageRecord) []UsageAggregate {
+	groups := make(map[string]*UsageAggregate)
+	for _, record := range records {
+		day := record.Time.Local().Format("2006-01-02")
+		key := day + "\x00" + record.Profile + "\x00" + record.Model
+		group, ok := groups[key]
+		if !ok {
+			group = &UsageAggregate{Day: day, Profile: record.Profile, Model: record.Model}
+			groups[key] = group
+		}
+		group.add(record)
+	}
+
+	result := make([]UsageAggregate, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Day != result[j].Day {
+			return result[i].Day < result[j].Day
+		}
+		if result[i].Profile != result[j].Profile {
+			return result[i].Profile < result[j].Profile
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
@@ -0,0 +1,305 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationIssue é um problema encontrado em um arquivo do projeto gerado
+type ValidationIssue struct {
+	Path      string `json:"path"`
+	Line      int    `json:"line,omitempty"`
+	Validator string `json:"validator"`
+	Message   string `json:"message"`
+}
+
+// String formata o problema como caminho:linha: mensagem
+func (i ValidationIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", i.Path, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+// ValidationReport reúne o resultado da validação de um projeto
+type ValidationReport struct {
+	// Files é a quantidade de arquivos verificados
+	Files int
+	// Validators lista os validadores executados
+	Validators []string
+	// Issues contém os problemas encontrados, ordenados por arquivo e linha
+	Issues []ValidationIssue
+}
+
+// OK indica se nenhum problema foi encontrado
+func (r *ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Paths retorna os arquivos com problemas, sem repetição
+func (r *ValidationReport) Paths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, issue := range r.Issues {
+		if !seen[issue.Path] {
+			seen[issue.Path] = true
+			paths = append(paths, issue.Path)
+		}
+	}
+	return paths
+}
+
+// String lista os problemas, um por linha, no formato usado também no prompt de correção
+func (r *ValidationReport) String() string {
+	var b strings.Builder
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "- [%s] %s\n", issue.Validator, issue)
+	}
+	return b.String()
+}
+
+// projectFiles é a visão em memória do projeto usada pelos validadores
+type projectFiles struct {
+	files map[string]string
+	dirs  map[string]bool
+}
+
+// exists indica se rel é um arquivo ou diretório do projeto
+func (p *projectFiles) exists(rel string) bool {
+	rel = path.Clean(rel)
+	_, ok := p.files[rel]
+	return ok || p.dirs[rel] || rel == "."
+}
+
+// sortedPaths retorna os arquivos do projeto em ordem alfabética
+func (p *projectFiles) sortedPaths() []string {
+	paths := make([]string, 0, len(p.files))
+	for rel := range p.files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// projectValidator verifica o projeto e retorna os problemas encontrados
+type projectValidator func(p *projectFiles) []ValidationIssue
+
+// syntaxValidators rodam em qualquer projeto e verificam os arquivos de dados pela extensão
+var syntaxValidators = []string{"json", "yaml", "toml"}
+
+// projectValidators são os validadores disponíveis, referenciados pelos pacotes de linguagem
+var projectValidators = map[string]projectValidator{
+	"json":         validateJSONFiles,
+	"yaml":         validateYAMLFiles,
+	"toml":         validateTOMLFiles,
+	"package-json": validatePackageJSON,
+	"js-imports":   validateJSImports,
+	"go":           validateGoFiles,
+	"go-mod":       validateGoMod,
+	"python":       validatePythonImports,
+	"pyproject":    validatePyproject,
+	"cargo":        validateCargo,
+	"rust-modules": validateRustModules,
+}
+
+// ValidatorNames lista os validadores disponíveis
+func ValidatorNames() []string {
+	names := make([]string, 0, len(projectValidators))
+	for name := range projectValidators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateProject verifica offline o projeto em dir: os arquivos JSON, YAML e TOML sempre
+// são analisados, e os validadores informados (normalmente os do pacote da linguagem)
+// conferem manifestos, pontos de entrada e imports locais.
+func ValidateProject(dir string, validators []string) (*ValidationReport, error) {
+	project := &projectFiles{files: make(map[string]string), dirs: make(map[string]bool)}
+	err := walkProjectFiles(dir, func(rel string) {
+		project.dirs[rel] = true
+	}, func(rel string, data []byte) error {
+		project.files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler projeto: %v", err)
+	}
+
+	report := &ValidationReport{Files: len(project.files)}
+	for _, name := range uniqueStrings(append(append([]string(nil), syntaxValidators...), validators...)) {
+		validator, ok := projectValidators[name]
+		if !ok {
+			return nil, fmt.Errorf("validador desconhecido '%s'; disponíveis: %s", name, strings.Join(ValidatorNames(), ", "))
+		}
+		report.Validators = append(report.Validators, name)
+		for _, issue := range validator(project) {
+			issue.Validator = name
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Message < b.Message
+	})
+	return report, nil
+}
+
+// lineAt converte um deslocamento em bytes no número da linha correspondente
+func lineAt(content string, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+// isJSONC indica arquivos JSON que, por convenção, aceitam comentários e vírgulas finais
+func isJSONC(rel string) bool {
+	base := path.Base(rel)
+	return strings.HasPrefix(base, "tsconfig") || strings.HasPrefix(base, "jsconfig") ||
+		base == ".eslintrc.json" || strings.HasPrefix(rel, ".vscode/") || strings.Contains(rel, "/.vscode/")
+}
+
+// stripJSONC remove comentários e vírgulas finais preservando as quebras de linha,
+// para que a posição dos erros continue correspondendo ao arquivo original
+func stripJSONC(content string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString:
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				b.WriteByte(content[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				b.WriteByte(' ')
+				i++
+			}
+			if i < len(content) {
+				b.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 2
+			} else {
+				end += 2
+			}
+			for _, r := range content[i : i+2+end] {
+				if r == '\n' {
+					b.WriteByte('\n')
+				} else {
+					b.WriteByte(' ')
+				}
+			}
+			i += 1 + end
+		case c == ',':
+			rest := strings.TrimLeft(content[i+1:], " \t\r\n")
+			if strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "]") {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte(c)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// jsonIssue converte um erro de json.Unmarshal em um problema com a linha do erro
+func jsonIssue(rel, content string, err error) ValidationIssue {
+	issue := ValidationIssue{Path: rel, Message: fmt.Sprintf("JSON inválido: %v", err)}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		issue.Line = lineAt(content, int(syntaxErr.Offset))
+	}
+	return issue
+}
+
+func validateJSONFiles(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".json" {
+			continue
+		}
+		content := p.files[rel]
+		if isJSONC(rel) {
+			content = stripJSONC(content)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(content), &value); err != nil {
+			issues = append(issues, jsonIssue(rel, content, err))
+		}
+	}
+	return issues
+}
+
+func validateYAMLFiles(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if ext := path.Ext(rel); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		// Um arquivo YAML pode conter vários documentos separados por ---
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(p.files[rel])))
+		for {
+			var value interface{}
+			err := decoder.Decode(&value)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf("YAML inválido: %v", err)})
+				break
+			}
+		}
+	}
+	return issues
+}
+
+func validateTOMLFiles(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".toml" {
+			continue
+		}
+		var value map[string]interface{}
+		if _, err := toml.Decode(p.files[rel], &value); err != nil {
+			issue := ValidationIssue{Path: rel, Message: fmt.Sprintf("TOML inválido: %v", err)}
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				issue.Line = parseErr.Position.Line
+				issue.Message = fmt.Sprintf("TOML inválido: %s", parseErr.Message)
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,555 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/mod/modfile"
+)
+
+// maxIssuesPerFile limita os erros de sintaxe reportados para um mesmo arquivo
+const maxIssuesPerFile = 5
+
+// buildOutputDirs são diretórios gerados pelo build, que não existem logo após o scaffold
+var buildOutputDirs = map[string]bool{
+	"dist":  true,
+	"build": true,
+	"lib":   true,
+	"out":   true,
+}
+
+// jsExtensions são as extensões tentadas ao resolver um import relativo sem extensão
+var jsExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx", ".mjs", ".cjs", ".mts", ".cts", ".json", ".vue", ".svelte"}
+
+// jsSourceExtensions são os arquivos cujos imports são verificados
+var jsSourceExtensions = map[string]bool{
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".mts": true, ".cts": true, ".vue": true, ".svelte": true,
+}
+
+var (
+	jsImportPattern      = regexp.MustCompile(`(?:\bimport|\bexport)\s[^'";]*?\bfrom\s*['"]([^'"\n]+)['"]|\bimport\s*['"]([^'"\n]+)['"]|\b(?:require|import)\(\s*['"]([^'"\n]+)['"]\s*\)`)
+	pythonFromPattern    = regexp.MustCompile(`(?m)^[ \t]*from[ \t]+(\.*)([\w.]*)[ \t]+import[ \t]+\(?[ \t]*([\w., \t]*)`)
+	pythonImportPattern  = regexp.MustCompile(`(?m)^[ \t]*import[ \t]+([\w.]+(?:[ \t]*,[ \t]*[\w.]+)*)`)
+	rustModPattern       = regexp.MustCompile(`(?m)^([ \t]*#\[path[^\n]*\n)?[ \t]*(?:pub(?:\([^)]*\))?[ \t]+)?mod[ \t]+(\w+)[ \t]*;`)
+	packageScriptRunners = map[string]bool{"node": true, "ts-node": true, "tsx": true, "nodemon": true, "bun": true, "deno": true}
+)
+
+// resolveJSModule procura o arquivo referenciado por um caminho de import, tentando as
+// extensões usuais, arquivos index e o arquivo .ts equivalente a um import .js
+func resolveJSModule(p *projectFiles, target string) bool {
+	target = path.Clean(target)
+	if _, ok := p.files[target]; ok {
+		return true
+	}
+	candidates := []string{target}
+	switch ext := path.Ext(target); ext {
+	case ".js", ".jsx", ".mjs", ".cjs":
+		// Projetos TypeScript com ESM importam o arquivo compilado (.js) a partir do fonte (.ts)
+		candidates = append(candidates, strings.TrimSuffix(target, ext))
+	}
+	for _, candidate := range candidates {
+		for _, ext := range jsExtensions {
+			if _, ok := p.files[candidate+ext]; ok {
+				return true
+			}
+			if _, ok := p.files[candidate+"/index"+ext]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveEntrypoint verifica um ponto de entrada declarado em relação ao diretório base.
+// Caminhos dentro de diretórios de build são aceitos quando existe o fonte equivalente em src/.
+func resolveEntrypoint(p *projectFiles, base, ref string) bool {
+	target := path.Join(base, ref)
+	if p.exists(target) || resolveJSModule(p, target) {
+		return true
+	}
+	rel := strings.TrimPrefix(path.Clean(ref), "./")
+	first := strings.SplitN(rel, "/", 2)[0]
+	if !buildOutputDirs[first] {
+		return false
+	}
+	rest := strings.TrimPrefix(rel, first)
+	return resolveJSModule(p, path.Join(base, "src", rest)) || resolveJSModule(p, path.Join(base, rest))
+}
+
+func validatePackageJSON(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Base(rel) != "package.json" {
+			continue
+		}
+		base := path.Dir(rel)
+		pkg, err := ParsePackageJSON([]byte(p.files[rel]))
+		if err != nil {
+			// O erro de sintaxe já é reportado pelo validador json
+			continue
+		}
+		add := func(format string, args ...interface{}) {
+			issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf(format, args...)})
+		}
+		for _, problem := range pkg.Problems() {
+			add("%s", problem)
+		}
+
+		for _, field := range []string{"main", "module", "types", "typings"} {
+			var ref string
+			if raw, ok := pkg.Field(field); ok && json.Unmarshal(raw, &ref) == nil && ref != "" {
+				if !resolveEntrypoint(p, base, ref) {
+					add(`"%s" aponta para %s, que não existe no projeto`, field, ref)
+				}
+			}
+		}
+		if raw, ok := pkg.Field("bin"); ok {
+			var single string
+			var multiple map[string]string
+			switch {
+			case json.Unmarshal(raw, &single) == nil:
+				multiple = map[string]string{"": single}
+			case json.Unmarshal(raw, &multiple) == nil:
+			default:
+				add(`"bin" deve ser um texto ou um objeto`)
+			}
+			for _, ref := range multiple {
+				if !resolveEntrypoint(p, base, ref) {
+					add(`"bin" aponta para %s, que não existe no projeto`, ref)
+				}
+			}
+		}
+
+		var scripts map[string]string
+		if raw, ok := pkg.Field("scripts"); ok {
+			if err := json.Unmarshal(raw, &scripts); err != nil {
+				add(`"scripts" deve ser um objeto de textos`)
+			}
+		}
+		for script, command := range scripts {
+			fields := strings.Fields(command)
+			for i := 0; i+1 < len(fields); i++ {
+				if !packageScriptRunners[fields[i]] {
+					continue
+				}
+				ref := fields[i+1]
+				if strings.HasPrefix(ref, "-") || !jsSourceExtensions[path.Ext(ref)] {
+					continue
+				}
+				if !resolveEntrypoint(p, base, ref) {
+					add(`script "%s" executa %s, que não existe no projeto`, script, ref)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+func validateJSImports(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if !jsSourceExtensions[path.Ext(rel)] || strings.HasSuffix(rel, ".d.ts") {
+			continue
+		}
+		content := p.files[rel]
+		for _, match := range jsImportPattern.FindAllStringSubmatchIndex(content, -1) {
+			var spec string
+			for group := 1; group <= 3; group++ {
+				if match[2*group] >= 0 {
+					spec = content[match[2*group]:match[2*group+1]]
+				}
+			}
+			if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+				continue
+			}
+			// Ignora sufixos usados por bundlers, como ?raw e #hash
+			target := strings.SplitN(strings.SplitN(spec, "?", 2)[0], "#", 2)[0]
+			if !resolveJSModule(p, path.Join(path.Dir(rel), target)) {
+				issues = append(issues, ValidationIssue{
+					Path:    rel,
+					Line:    lineAt(content, match[0]),
+					Message: fmt.Sprintf("import de '%s' não corresponde a nenhum arquivo do projeto", spec),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// nearestFile procura o arquivo name no diretório de rel ou em um de seus ancestrais
+func nearestFile(p *projectFiles, rel, name string) (string, bool) {
+	for dir := path.Dir(rel); ; dir = path.Dir(dir) {
+		candidate := path.Join(dir, name)
+		if _, ok := p.files[candidate]; ok {
+			return candidate, true
+		}
+		if dir == "." {
+			return "", false
+		}
+	}
+}
+
+func validateGoFiles(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	goDirs := make(map[string]bool)
+	packages := make(map[string]string)
+	fset := token.NewFileSet()
+
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".go" {
+			continue
+		}
+		goDirs[path.Dir(rel)] = true
+	}
+
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".go" {
+			continue
+		}
+		file, err := parser.ParseFile(fset, rel, p.files[rel], parser.AllErrors)
+		if err != nil {
+			var list scanner.ErrorList
+			if errors.As(err, &list) {
+				// Mantém apenas o primeiro erro de cada linha
+				list.RemoveMultiples()
+				for i, e := range list {
+					if i == maxIssuesPerFile {
+						issues = append(issues, ValidationIssue{Path: rel, Line: e.Pos.Line, Message: fmt.Sprintf("... mais %d erros de sintaxe", len(list)-i)})
+						break
+					}
+					issues = append(issues, ValidationIssue{Path: rel, Line: e.Pos.Line, Message: e.Msg})
+				}
+			} else {
+				issues = append(issues, ValidationIssue{Path: rel, Message: err.Error()})
+			}
+		}
+		if file == nil || file.Name == nil {
+			continue
+		}
+
+		// Todos os arquivos de um diretório devem declarar o mesmo pacote (exceto pacotes _test)
+		dir := path.Dir(rel)
+		name := file.Name.Name
+		if !strings.HasSuffix(name, "_test") {
+			if existing, ok := packages[dir]; !ok {
+				packages[dir] = name
+			} else if existing != name {
+				issues = append(issues, ValidationIssue{
+					Path:    rel,
+					Line:    fset.Position(file.Name.Pos()).Line,
+					Message: fmt.Sprintf("pacote %s difere do pacote %s dos demais arquivos do diretório", name, existing),
+				})
+			}
+		}
+
+		goMod, ok := nearestFile(p, rel, "go.mod")
+		if !ok {
+			continue
+		}
+		modulePath := modfile.ModulePath([]byte(p.files[goMod]))
+		if modulePath == "" {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || (importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/")) {
+				continue
+			}
+			target := path.Join(path.Dir(goMod), strings.TrimPrefix(importPath, modulePath))
+			if !goDirs[target] {
+				issues = append(issues, ValidationIssue{
+					Path:    rel,
+					Line:    fset.Position(spec.Pos()).Line,
+					Message: fmt.Sprintf("import %q não corresponde a nenhum pacote do módulo (esperado em %s/)", importPath, target),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+func validateGoMod(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Base(rel) != "go.mod" {
+			continue
+		}
+		file, err := modfile.Parse(rel, []byte(p.files[rel]), nil)
+		if err != nil {
+			var list modfile.ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					issues = append(issues, ValidationIssue{Path: rel, Line: e.Pos.Line, Message: e.Err.Error()})
+				}
+			} else {
+				issues = append(issues, ValidationIssue{Path: rel, Message: err.Error()})
+			}
+			continue
+		}
+		if file.Module == nil {
+			issues = append(issues, ValidationIssue{Path: rel, Message: "diretiva module ausente"})
+		}
+		if file.Go == nil {
+			issues = append(issues, ValidationIssue{Path: rel, Message: "diretiva go ausente"})
+		}
+	}
+	return issues
+}
+
+// pythonRoots são os diretórios onde ficam os pacotes de nível superior de um projeto Python
+var pythonRoots = []string{".", "src"}
+
+// resolvePythonModule verifica se o módulo (caminho com /) existe como arquivo .py ou pacote
+func resolvePythonModule(p *projectFiles, target string) bool {
+	target = path.Clean(target)
+	if _, ok := p.files[target+".py"]; ok {
+		return true
+	}
+	return p.dirs[target]
+}
+
+// localPythonRoot retorna a raiz onde fica o pacote de nível superior top, se ele for do projeto
+func localPythonRoot(p *projectFiles, top string) (string, bool) {
+	for _, root := range pythonRoots {
+		if resolvePythonModule(p, path.Join(root, top)) {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func validatePythonImports(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".py" {
+			continue
+		}
+		content := p.files[rel]
+		add := func(offset int, format string, args ...interface{}) {
+			issues = append(issues, ValidationIssue{Path: rel, Line: lineAt(content, offset), Message: fmt.Sprintf(format, args...)})
+		}
+
+		for _, match := range pythonFromPattern.FindAllStringSubmatchIndex(content, -1) {
+			dots := content[match[2]:match[3]]
+			module := content[match[4]:match[5]]
+			names := content[match[6]:match[7]]
+
+			if dots == "" {
+				top := strings.SplitN(module, ".", 2)[0]
+				if root, ok := localPythonRoot(p, top); ok && !resolvePythonModule(p, path.Join(root, strings.ReplaceAll(module, ".", "/"))) {
+					add(match[0], "módulo '%s' não existe no projeto", module)
+				}
+				continue
+			}
+
+			base := path.Dir(rel)
+			for i := 1; i < len(dots); i++ {
+				base = path.Dir(base)
+			}
+			if module != "" {
+				if !resolvePythonModule(p, path.Join(base, strings.ReplaceAll(module, ".", "/"))) {
+					add(match[0], "import relativo '%s%s' não corresponde a nenhum arquivo do projeto", dots, module)
+				}
+				continue
+			}
+			// from . import a, b: cada nome é um módulo ou um nome definido no __init__.py
+			if _, ok := p.files[path.Join(base, "__init__.py")]; ok {
+				continue
+			}
+			for _, name := range strings.Split(names, ",") {
+				// Considera apenas o nome importado em "a as b"
+				fields := strings.Fields(name)
+				if len(fields) == 0 {
+					continue
+				}
+				if !resolvePythonModule(p, path.Join(base, fields[0])) {
+					add(match[0], "import relativo '%s%s' não corresponde a nenhum arquivo do projeto", dots, fields[0])
+				}
+			}
+		}
+
+		for _, match := range pythonImportPattern.FindAllStringSubmatchIndex(content, -1) {
+			for _, module := range strings.Split(content[match[2]:match[3]], ",") {
+				module = strings.TrimSpace(module)
+				top := strings.SplitN(module, ".", 2)[0]
+				if root, ok := localPythonRoot(p, top); ok && !resolvePythonModule(p, path.Join(root, strings.ReplaceAll(module, ".", "/"))) {
+					add(match[0], "módulo '%s' não existe no projeto", module)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+func validatePyproject(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Base(rel) != "pyproject.toml" {
+			continue
+		}
+		var pyproject struct {
+			Project *struct {
+				Name    string            `toml:"name"`
+				Scripts map[string]string `toml:"scripts"`
+			} `toml:"project"`
+			Tool struct {
+				Poetry *struct {
+					Name    string            `toml:"name"`
+					Scripts map[string]string `toml:"scripts"`
+				} `toml:"poetry"`
+			} `toml:"tool"`
+		}
+		if _, err := toml.Decode(p.files[rel], &pyproject); err != nil {
+			issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf("estrutura inesperada: %v", err)})
+			continue
+		}
+
+		scripts := make(map[string]string)
+		if pyproject.Project != nil {
+			if pyproject.Project.Name == "" {
+				issues = append(issues, ValidationIssue{Path: rel, Message: "campo name ausente em [project]"})
+			}
+			for name, ref := range pyproject.Project.Scripts {
+				scripts[name] = ref
+			}
+		}
+		if pyproject.Tool.Poetry != nil {
+			for name, ref := range pyproject.Tool.Poetry.Scripts {
+				scripts[name] = ref
+			}
+		}
+
+		base := path.Dir(rel)
+		for name, ref := range scripts {
+			module := strings.TrimSpace(strings.SplitN(ref, ":", 2)[0])
+			found := false
+			for _, root := range pythonRoots {
+				if resolvePythonModule(p, path.Join(base, root, strings.ReplaceAll(module, ".", "/"))) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf("script '%s' aponta para o módulo '%s', que não existe no projeto", name, module)})
+			}
+		}
+	}
+	return issues
+}
+
+func validateCargo(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Base(rel) != "Cargo.toml" {
+			continue
+		}
+		var cargo struct {
+			Package *struct {
+				Name string `toml:"name"`
+			} `toml:"package"`
+			Lib *struct {
+				Path string `toml:"path"`
+			} `toml:"lib"`
+			Bin []struct {
+				Name string `toml:"name"`
+				Path string `toml:"path"`
+			} `toml:"bin"`
+			Workspace *struct {
+				Members []string `toml:"members"`
+			} `toml:"workspace"`
+		}
+		if _, err := toml.Decode(p.files[rel], &cargo); err != nil {
+			issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf("estrutura inesperada: %v", err)})
+			continue
+		}
+		base := path.Dir(rel)
+		add := func(format string, args ...interface{}) {
+			issues = append(issues, ValidationIssue{Path: rel, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if cargo.Package == nil && cargo.Workspace == nil {
+			add("seção [package] ou [workspace] ausente")
+		}
+		if cargo.Package != nil {
+			if cargo.Package.Name == "" {
+				add("campo name ausente em [package]")
+			}
+			hasTarget := p.exists(path.Join(base, "src/main.rs")) || p.exists(path.Join(base, "src/lib.rs"))
+			if cargo.Lib != nil && cargo.Lib.Path != "" {
+				hasTarget = true
+				if !p.exists(path.Join(base, cargo.Lib.Path)) {
+					add("[lib] aponta para %s, que não existe no projeto", cargo.Lib.Path)
+				}
+			}
+			for _, bin := range cargo.Bin {
+				if bin.Path == "" {
+					continue
+				}
+				hasTarget = true
+				if !p.exists(path.Join(base, bin.Path)) {
+					add("[[bin]] %s aponta para %s, que não existe no projeto", bin.Name, bin.Path)
+				}
+			}
+			if !hasTarget {
+				add("nenhum alvo encontrado: crie src/main.rs ou src/lib.rs")
+			}
+		}
+		if cargo.Workspace != nil {
+			for _, member := range cargo.Workspace.Members {
+				if strings.ContainsAny(member, "*?[") {
+					continue
+				}
+				if !p.exists(path.Join(base, member, "Cargo.toml")) {
+					add("membro do workspace %s não possui Cargo.toml", member)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// rustCrateRoots e rustCrateRootDirs identificam arquivos que são raiz de um crate ou de um módulo com diretório próprio
+var rustCrateRoots = map[string]bool{"main.rs": true, "lib.rs": true, "mod.rs": true}
+var rustCrateRootDirs = map[string]bool{"bin": true, "tests": true, "examples": true, "benches": true}
+
+func validateRustModules(p *projectFiles) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, rel := range p.sortedPaths() {
+		if path.Ext(rel) != ".rs" {
+			continue
+		}
+		content := p.files[rel]
+		// Em main.rs, lib.rs, mod.rs e nas raízes de src/bin, tests, examples e benches os
+		// submódulos ficam no mesmo diretório; nos demais arquivos, em um diretório com o nome do arquivo
+		dir := path.Dir(rel)
+		base := path.Base(rel)
+		if !rustCrateRoots[base] && !rustCrateRootDirs[path.Base(dir)] {
+			dir = path.Join(dir, strings.TrimSuffix(base, ".rs"))
+		}
+		for _, match := range rustModPattern.FindAllStringSubmatchIndex(content, -1) {
+			if match[2] >= 0 {
+				// #[path = "..."] define outro caminho para o módulo
+				continue
+			}
+			name := content[match[4]:match[5]]
+			if !p.exists(path.Join(dir, name+".rs")) && !p.exists(path.Join(dir, name, "mod.rs")) {
+				issues = append(issues, ValidationIssue{
+					Path:    rel,
+					Line:    lineAt(content, match[4]),
+					Message: fmt.Sprintf("mod %s; não corresponde a %s.rs nem a %s/mod.rs", name, path.Join(dir, name), path.Join(dir, name)),
+				})
+			}
+		}
+	}
+	return issues
+}
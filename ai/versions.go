@@ -0,0 +1,508 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// version é uma versão publicada em um registro de pacotes: semver (npm, Go, Cargo) ou
+// PEP 440 (Python), simplificado para o necessário à escolha da versão mais recente
+type version struct {
+	release []int
+	// pre é o identificador de pré-lançamento; versões com pre vêm antes da versão final
+	pre  string
+	post int
+	raw  string
+}
+
+var (
+	semverVersionPattern  = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+	pep440VersionPattern  = regexp.MustCompile(`^v?(?:\d+!)?(\d+(?:\.\d+)*)(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?(?:(?:-|[-_.]?(?:post|rev|r)[-_.]?)(\d+))?(?:[-_.]?dev[-_.]?(\d*))?(?:\+[a-z0-9.]+)?$`)
+	partialVersionPattern = regexp.MustCompile(`^v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+)
+
+// parseSemver interpreta uma versão semver completa, com ou sem o prefixo v
+func parseSemver(s string) (version, bool) {
+	m := semverVersionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return version{}, false
+	}
+	v := version{pre: m[4], raw: s}
+	for _, part := range m[1:4] {
+		n, _ := strconv.Atoi(part)
+		v.release = append(v.release, n)
+	}
+	return v, true
+}
+
+// parsePEP440 interpreta uma versão Python. Pré-lançamentos (a, b, rc) e versões de
+// desenvolvimento ficam em pre; dev vem antes dos demais pré-lançamentos.
+func parsePEP440(s string) (version, bool) {
+	m := pep440VersionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return version{}, false
+	}
+	v := version{raw: s}
+	for _, part := range strings.Split(m[1], ".") {
+		n, _ := strconv.Atoi(part)
+		v.release = append(v.release, n)
+	}
+	if m[2] != "" {
+		kind := map[string]string{"alpha": "a", "beta": "b", "c": "rc", "pre": "rc", "preview": "rc"}[m[2]]
+		if kind == "" {
+			kind = m[2]
+		}
+		v.pre = kind + "." + numberOrZero(m[3])
+	}
+	if m[4] != "" {
+		v.post, _ = strconv.Atoi(m[4])
+	}
+	if m[5] != "" || strings.Contains(m[0], "dev") {
+		v.pre = "0dev." + numberOrZero(m[5])
+	}
+	return v, true
+}
+
+func numberOrZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// compareVersions compara duas versões como semver: componentes ausentes valem zero e
+// uma versão com pré-lançamento é menor que a mesma versão final
+func compareVersions(a, b version) int {
+	for i := 0; i < len(a.release) || i < len(b.release); i++ {
+		var x, y int
+		if i < len(a.release) {
+			x = a.release[i]
+		}
+		if i < len(b.release) {
+			y = b.release[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.pre == "" && b.pre != "":
+		return 1
+	case a.pre != "" && b.pre == "":
+		return -1
+	case a.pre != b.pre:
+		return comparePrerelease(a.pre, b.pre)
+	case a.post != b.post:
+		if a.post < b.post {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compara identificadores de pré-lançamento separados por pontos:
+// numéricos entre si pelo valor e antes dos alfanuméricos
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		switch {
+		case errX == nil && errY == nil:
+			if x != y {
+				if x < y {
+					return -1
+				}
+				return 1
+			}
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		case as[i] != bs[i]:
+			if as[i] < bs[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// versionBound é um comparador simples, como >=1.2.0
+type versionBound struct {
+	op string
+	v  version
+}
+
+func (b versionBound) allows(v version) bool {
+	c := compareVersions(v, b.v)
+	switch b.op {
+	case ">=":
+		return c >= 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	case "<":
+		return c < 0
+	case "!=":
+		return c != 0
+	}
+	return c == 0
+}
+
+// versionSpec é a especificação de versão de uma dependência já interpretada
+type versionSpec struct {
+	// sets são alternativas (||) de comparadores que precisam valer juntos
+	sets [][]versionBound
+	// op é o operador do comparador único de uma especificação simples, como ^ em ^1.2.3;
+	// especificações compostas têm simple = false
+	op     string
+	simple bool
+	// components é o número de componentes da versão escrita na especificação simples
+	components int
+	// major é a versão major da primeira versão escrita na especificação, ou -1
+	major int
+	// pre indica que a especificação menciona um pré-lançamento
+	pre bool
+}
+
+// allows indica se a versão satisfaz a especificação. Pré-lançamentos só são aceitos
+// quando a especificação menciona algum.
+func (s versionSpec) allows(v version) bool {
+	if v.pre != "" && !s.pre {
+		return false
+	}
+	for _, set := range s.sets {
+		ok := true
+		for _, bound := range set {
+			if !bound.allows(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rewrite monta a especificação que aponta para a versão resolvida, mantendo o operador
+// da especificação original. Com ~ e ~=, que limitam a faixa pela quantidade de
+// componentes, essa quantidade também é mantida.
+func (s versionSpec) rewrite(resolved version, defaultOp string) string {
+	op := defaultOp
+	if s.simple {
+		op = s.op
+	}
+	text := strings.TrimPrefix(resolved.raw, "v")
+	if s.simple && (op == "~" || op == "~>" || op == "~=") && s.components < len(resolved.release) && s.components > 0 {
+		parts := make([]string, s.components)
+		for i := range parts {
+			parts[i] = strconv.Itoa(resolved.release[i])
+		}
+		text = strings.Join(parts, ".")
+	}
+	return op + text
+}
+
+// partialSemver é uma versão possivelmente incompleta, como 1.2 ou 1.x
+type partialSemver struct {
+	parts []int
+	// n é o número de componentes informados antes do primeiro curinga
+	n   int
+	pre string
+}
+
+func parsePartial(s string) (partialSemver, bool) {
+	m := partialVersionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return partialSemver{}, false
+	}
+	p := partialSemver{parts: make([]int, 3), pre: m[4]}
+	for i, part := range m[1:4] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		p.parts[i] = n
+		p.n++
+	}
+	return p, true
+}
+
+func (p partialSemver) lower() version {
+	return version{release: append([]int(nil), p.parts...), pre: p.pre}
+}
+
+// next retorna a menor versão acima de todas as que têm os mesmos componentes até i
+func (p partialSemver) next(i int) version {
+	release := make([]int, len(p.parts))
+	copy(release, p.parts[:i])
+	release[i] = p.parts[i] + 1
+	// O pré-lançamento 0 exclui os pré-lançamentos da próxima versão da faixa
+	return version{release: release, pre: "0"}
+}
+
+// semverBounds converte um comparador npm ou Cargo em limites simples
+func semverBounds(op string, p partialSemver) []versionBound {
+	if p.n == 0 {
+		return nil
+	}
+	exact := p.n == len(p.parts)
+	switch op {
+	case "^":
+		i := 0
+		for i < p.n-1 && p.parts[i] == 0 {
+			i++
+		}
+		return []versionBound{{">=", p.lower()}, {"<", p.next(i)}}
+	case "~", "~>":
+		i := 1
+		if p.n == 1 {
+			i = 0
+		}
+		return []versionBound{{">=", p.lower()}, {"<", p.next(i)}}
+	case ">":
+		if exact {
+			return []versionBound{{">", p.lower()}}
+		}
+		return []versionBound{{">=", p.next(p.n - 1)}}
+	case ">=":
+		return []versionBound{{">=", p.lower()}}
+	case "<":
+		return []versionBound{{"<", p.lower()}}
+	case "<=":
+		if exact {
+			return []versionBound{{"<=", p.lower()}}
+		}
+		return []versionBound{{"<", p.next(p.n - 1)}}
+	}
+	if exact {
+		return []versionBound{{"=", p.lower()}}
+	}
+	return []versionBound{{">=", p.lower()}, {"<", p.next(p.n - 1)}}
+}
+
+var semverOperatorPattern = regexp.MustCompile(`^(>=|<=|>|<|=|~>|~|\^)?\s*(.+)$`)
+
+// parseNPMSpec interpreta uma faixa de versão do npm. Especificações que não são faixas
+// semver (tags, caminhos, repositórios git, URLs) retornam erro.
+func parseNPMSpec(spec string) (versionSpec, error) {
+	s := versionSpec{major: -1}
+	comparators := 0
+	for _, set := range strings.Split(spec, "||") {
+		set = strings.TrimSpace(set)
+		var bounds []versionBound
+		if parts := strings.Split(set, " - "); len(parts) == 2 {
+			from, okFrom := parsePartial(parts[0])
+			to, okTo := parsePartial(parts[1])
+			if !okFrom || !okTo {
+				return s, fmt.Errorf("faixa de versão inválida: %q", spec)
+			}
+			s.note(from)
+			bounds = append(semverBounds(">=", from), semverBounds("<=", to)...)
+			comparators += 2
+		} else {
+			tokens := strings.Fields(set)
+			for i := 0; i < len(tokens); i++ {
+				token := tokens[i]
+				if operatorPattern.MatchString(token) && i+1 < len(tokens) {
+					i++
+					token += tokens[i]
+				}
+				m := semverOperatorPattern.FindStringSubmatch(token)
+				if m == nil {
+					return s, fmt.Errorf("faixa de versão inválida: %q", spec)
+				}
+				p, ok := parsePartial(m[2])
+				if !ok {
+					return s, fmt.Errorf("faixa de versão inválida: %q", spec)
+				}
+				s.note(p)
+				s.op, s.components = m[1], p.n
+				bounds = append(bounds, semverBounds(m[1], p)...)
+				comparators++
+			}
+		}
+		s.sets = append(s.sets, bounds)
+	}
+	s.simple = comparators == 1 && len(s.sets) == 1 && s.components > 0 && isRewritableOp(s.op)
+	return s, nil
+}
+
+// parseCargoSpec interpreta os requisitos de versão do Cargo, separados por vírgula.
+// Uma versão sem operador equivale a ^.
+func parseCargoSpec(spec string) (versionSpec, error) {
+	s := versionSpec{major: -1}
+	var bounds []versionBound
+	comparators := strings.Split(spec, ",")
+	for _, comparator := range comparators {
+		m := semverOperatorPattern.FindStringSubmatch(strings.TrimSpace(comparator))
+		if m == nil {
+			return s, fmt.Errorf("requisito de versão inválido: %q", spec)
+		}
+		p, ok := parsePartial(m[2])
+		if !ok {
+			return s, fmt.Errorf("requisito de versão inválido: %q", spec)
+		}
+		op := m[1]
+		if op == "" {
+			op = "^"
+		}
+		s.note(p)
+		s.op, s.components = m[1], p.n
+		bounds = append(bounds, semverBounds(op, p)...)
+	}
+	s.sets = [][]versionBound{bounds}
+	s.simple = len(comparators) == 1 && s.components > 0 && isRewritableOp(s.op)
+	return s, nil
+}
+
+var pythonOperatorPattern = regexp.MustCompile(`^(===|==|!=|~=|>=|<=|>|<)\s*(.+)$`)
+
+// parsePythonSpec interpreta especificadores de versão PEP 440, separados por vírgula.
+// Com poetry, os operadores ^ e ~ e versões sem operador (exatas) também são aceitos.
+func parsePythonSpec(spec string, poetry bool) (versionSpec, error) {
+	s := versionSpec{major: -1}
+	var bounds []versionBound
+	comparators := strings.Split(spec, ",")
+	for _, comparator := range comparators {
+		comparator = strings.TrimSpace(comparator)
+		if poetry && comparator == "*" {
+			continue
+		}
+		if poetry && (strings.HasPrefix(comparator, "^") || (strings.HasPrefix(comparator, "~") && !strings.HasPrefix(comparator, "~="))) {
+			op := comparator[:1]
+			p, ok := parsePartial(comparator[1:])
+			if !ok {
+				return s, fmt.Errorf("especificador de versão inválido: %q", spec)
+			}
+			s.note(p)
+			s.op, s.components = op, p.n
+			bounds = append(bounds, semverBounds(op, p)...)
+			continue
+		}
+		bare := poetry && !pythonOperatorPattern.MatchString(comparator)
+		if bare {
+			comparator = "==" + comparator
+		}
+		m := pythonOperatorPattern.FindStringSubmatch(comparator)
+		if m == nil {
+			return s, fmt.Errorf("especificador de versão inválido: %q", spec)
+		}
+		op, text := m[1], strings.TrimSpace(m[2])
+		if strings.HasSuffix(text, ".*") {
+			v, ok := parsePEP440(strings.TrimSuffix(text, ".*"))
+			if !ok {
+				return s, fmt.Errorf("especificador de versão inválido: %q", spec)
+			}
+			s.noteVersion(v)
+			if op == "==" {
+				upper := append([]int(nil), v.release...)
+				upper[len(upper)-1]++
+				bounds = append(bounds, versionBound{">=", v}, versionBound{"<", version{release: upper, pre: "0"}})
+			}
+			// != com curinga exclui uma série inteira; como não afeta a escolha da versão
+			// mais recente na prática, é ignorado
+			s.op, s.components = "", 0
+			continue
+		}
+		v, ok := parsePEP440(text)
+		if !ok {
+			return s, fmt.Errorf("especificador de versão inválido: %q", spec)
+		}
+		s.noteVersion(v)
+		s.op, s.components = op, len(v.release)
+		if bare {
+			// Versões sem operador no poetry são exatas e continuam sem operador
+			s.op = ""
+		}
+		switch op {
+		case "~=":
+			if len(v.release) < 2 {
+				return s, fmt.Errorf("especificador de versão inválido: %q", spec)
+			}
+			upper := append([]int(nil), v.release[:len(v.release)-1]...)
+			upper[len(upper)-1]++
+			bounds = append(bounds, versionBound{">=", v}, versionBound{"<", version{release: upper, pre: "0"}})
+		case "===":
+			bounds = append(bounds, versionBound{"=", v})
+		default:
+			bounds = append(bounds, versionBound{strings.TrimPrefix(op, "="), v})
+		}
+	}
+	s.sets = [][]versionBound{bounds}
+	s.simple = len(comparators) == 1 && s.components > 0 && (s.op == "==" || s.op == ">=" || s.op == "~=" || s.op == "^" || s.op == "~" || poetry && s.op == "")
+	return s, nil
+}
+
+// isRewritableOp indica os operadores de especificações simples que podem ser reescritas
+// para a versão resolvida sem mudar seu sentido
+func isRewritableOp(op string) bool {
+	switch op {
+	case "", "=", "^", "~", "~>", ">=":
+		return true
+	}
+	return false
+}
+
+func (s *versionSpec) note(p partialSemver) {
+	if p.pre != "" {
+		s.pre = true
+	}
+	if s.major < 0 && p.n > 0 {
+		s.major = p.parts[0]
+	}
+}
+
+func (s *versionSpec) noteVersion(v version) {
+	if v.pre != "" {
+		s.pre = true
+	}
+	if s.major < 0 && len(v.release) > 0 {
+		s.major = v.release[0]
+	}
+}
+
+// latestVersion retorna a versão mais recente aceita por allow
+func latestVersion(versions []version, allow func(version) bool) (version, bool) {
+	var best version
+	found := false
+	for _, v := range versions {
+		if allow(v) && (!found || compareVersions(v, best) > 0) {
+			best, found = v, true
+		}
+	}
+	return best, found
+}
+
+// resolveVersion escolhe a versão mais recente publicada que satisfaz a especificação.
+// Se nenhuma satisfaz, como quando o modelo inventa uma versão, escolhe a mais recente
+// com a mesma versão major e indica que a faixa foi ampliada.
+func resolveVersion(versions []version, spec versionSpec) (resolved version, widened bool, ok bool) {
+	if resolved, ok = latestVersion(versions, spec.allows); ok {
+		return resolved, false, true
+	}
+	if spec.major < 0 {
+		return version{}, false, false
+	}
+	resolved, ok = latestVersion(versions, func(v version) bool {
+		return v.pre == "" && len(v.release) > 0 && v.release[0] == spec.major
+	})
+	return resolved, ok, ok
+}
@@ -0,0 +1,313 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Analisadores de especificação por ecossistema, no formato usado pelos testes
+var testSpecParsers = map[string]func(string) (versionSpec, error){
+	"npm":    parseNPMSpec,
+	"cargo":  parseCargoSpec,
+	"pypi":   func(spec string) (versionSpec, error) { return parsePythonSpec(spec, false) },
+	"poetry": func(spec string) (versionSpec, error) { return parsePythonSpec(spec, true) },
+}
+
+// testVersion interpreta uma versão do ecossistema: PEP 440 para Python, semver nos demais
+func testVersion(t *testing.T, ecosystem, s string) version {
+	t.Helper()
+	parse := parseSemver
+	if ecosystem == "pypi" || ecosystem == "poetry" {
+		parse = parsePEP440
+	}
+	v, ok := parse(s)
+	if !ok {
+		t.Fatalf("versão inválida no teste: %q", s)
+	}
+	return v
+}
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input   string
+		release []int
+		pre     string
+		ok      bool
+	}{
+		{"1.2.3", []int{1, 2, 3}, "", true},
+		{"v0.10.0", []int{0, 10, 0}, "", true},
+		{"1.0.0-beta.1", []int{1, 0, 0}, "beta.1", true},
+		{"1.0.0-rc.1+build.5", []int{1, 0, 0}, "rc.1", true},
+		{"2.0.0+20240101", []int{2, 0, 0}, "", true},
+		{"1.2", nil, "", false},
+		{"1.2.3.4", nil, "", false},
+		{"latest", nil, "", false},
+		{"", nil, "", false},
+	}
+	for _, tt := range tests {
+		v, ok := parseSemver(tt.input)
+		if ok != tt.ok || !reflect.DeepEqual(v.release, tt.release) || v.pre != tt.pre {
+			t.Errorf("parseSemver(%q) = %v %q %v, esperado %v %q %v", tt.input, v.release, v.pre, ok, tt.release, tt.pre, tt.ok)
+		}
+	}
+}
+
+func TestParsePEP440(t *testing.T) {
+	tests := []struct {
+		input   string
+		release []int
+		pre     string
+		post    int
+		ok      bool
+	}{
+		{"1.0", []int{1, 0}, "", 0, true},
+		{"2024.1.15", []int{2024, 1, 15}, "", 0, true},
+		{"2.0.0rc1", []int{2, 0, 0}, "rc.1", 0, true},
+		{"1.0a", []int{1, 0}, "a.0", 0, true},
+		{"1.0-alpha.2", []int{1, 0}, "a.2", 0, true},
+		{"1.0.0.beta3", []int{1, 0, 0}, "b.3", 0, true},
+		{"1.0c1", []int{1, 0}, "rc.1", 0, true},
+		{"1.0.post2", []int{1, 0}, "", 2, true},
+		{"1.0-1", []int{1, 0}, "", 1, true},
+		{"1.0.dev3", []int{1, 0}, "0dev.3", 0, true},
+		{"1.0b2.dev1", []int{1, 0}, "0dev.1", 0, true},
+		{"1!2.0", []int{2, 0}, "", 0, true},
+		{"1.0+ubuntu.1", []int{1, 0}, "", 0, true},
+		{"V1.0", []int{1, 0}, "", 0, true},
+		{"1.0.x", nil, "", 0, false},
+		{"banana", nil, "", 0, false},
+	}
+	for _, tt := range tests {
+		v, ok := parsePEP440(tt.input)
+		if ok != tt.ok || !reflect.DeepEqual(v.release, tt.release) || v.pre != tt.pre || v.post != tt.post {
+			t.Errorf("parsePEP440(%q) = %v %q post %d %v, esperado %v %q post %d %v",
+				tt.input, v.release, v.pre, v.post, ok, tt.release, tt.pre, tt.post, tt.ok)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// Cada lista está em ordem crescente; versões iguais ficam no mesmo item, separadas por =
+	tests := []struct {
+		ecosystem string
+		ordered   []string
+	}{
+		{"npm", []string{
+			"0.9.9", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+			"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0=v1.0.0=1.0.0+build", "1.2.3", "1.10.0", "10.0.0",
+		}},
+		{"pypi", []string{
+			"0.9", "1.0.dev1", "1.0a1", "1.0b1", "1.0rc1", "1.0=1.0.0=1.0+local", "1.0.post1", "1.0.1", "1.10",
+		}},
+	}
+	for _, tt := range tests {
+		var groups [][]version
+		for _, item := range tt.ordered {
+			var group []version
+			for _, s := range strings.Split(item, "=") {
+				group = append(group, testVersion(t, tt.ecosystem, s))
+			}
+			groups = append(groups, group)
+		}
+		for i, gi := range groups {
+			for j, gj := range groups {
+				want := 0
+				if i < j {
+					want = -1
+				} else if i > j {
+					want = 1
+				}
+				for _, a := range gi {
+					for _, b := range gj {
+						if got := compareVersions(a, b); got != want {
+							t.Errorf("compareVersions(%s, %s) = %d, esperado %d", a.raw, b.raw, got, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVersionSpecAllows(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		spec      string
+		allowed   []string
+		rejected  []string
+	}{
+		// npm
+		{"npm", "^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "1.3.0-beta.1"}},
+		{"npm", "^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"npm", "^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.1.0"}},
+		{"npm", "^1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0"}},
+		{"npm", "~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"npm", "~1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{"npm", "1.x", []string{"1.0.0", "1.5.0"}, []string{"2.0.0", "0.9.0"}},
+		{"npm", "1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"npm", "=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"npm", "*", []string{"0.0.1", "3.0.0"}, []string{"3.1.0-rc.1"}},
+		{"npm", ">=1.0.0 <2.0.0", []string{"1.0.0", "1.9.9"}, []string{"0.9.0", "2.0.0"}},
+		{"npm", ">= 1.2.0", []string{"1.2.0", "5.0.0"}, []string{"1.1.0"}},
+		{"npm", ">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"npm", "<=1.2", []string{"1.2.9", "0.1.0"}, []string{"1.3.0"}},
+		{"npm", "<2", []string{"1.9.9"}, []string{"2.0.0", "2.0.0-rc.1"}},
+		{"npm", "1.2.3 - 2.3", []string{"1.2.3", "2.3.9"}, []string{"1.2.2", "2.4.0"}},
+		{"npm", "^1.0.0 || ^3.0.0", []string{"1.5.0", "3.1.0"}, []string{"2.0.0", "4.0.0"}},
+		{"npm", "^1.0.0-beta.1", []string{"1.0.0-beta.2", "1.0.0", "1.4.0"}, []string{"1.0.0-alpha", "2.0.0"}},
+		// Cargo: versões sem operador equivalem a ^
+		{"cargo", "1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0"}},
+		{"cargo", "0.3", []string{"0.3.5"}, []string{"0.4.0"}},
+		{"cargo", "=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"cargo", "~1.2", []string{"1.2.7"}, []string{"1.3.0"}},
+		{"cargo", ">=1.2, <1.5", []string{"1.2.0", "1.4.9"}, []string{"1.1.0", "1.5.0"}},
+		{"cargo", "*", []string{"0.1.0", "9.0.0"}, nil},
+		// PEP 440
+		{"pypi", "==1.2.3", []string{"1.2.3", "1.2.3.0"}, []string{"1.2.4"}},
+		{"pypi", ">=2.0,<3", []string{"2.0", "2.9.1"}, []string{"1.9", "3.0", "3.0rc1"}},
+		{"pypi", "~=1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.5.0", "1.4.1"}},
+		{"pypi", "~=2.2", []string{"2.2", "2.9"}, []string{"3.0", "2.1"}},
+		{"pypi", "==1.4.*", []string{"1.4.0", "1.4.7"}, []string{"1.5.0", "1.3.9"}},
+		{"pypi", "!=1.5.0, >=1.0", []string{"1.0", "1.5.1"}, []string{"1.5.0", "0.9"}},
+		{"pypi", ">=1.0rc1", []string{"1.0rc2", "1.0", "2.0"}, []string{"1.0b1"}},
+		{"pypi", "===2.0", []string{"2.0"}, []string{"2.0.1"}},
+		{"pypi", "<2", []string{"1.9"}, []string{"2.0", "2.0.dev1"}},
+		// Poetry
+		{"poetry", "^1.2", []string{"1.2", "1.9"}, []string{"1.1", "2.0"}},
+		{"poetry", "^0.4", []string{"0.4.9"}, []string{"0.5.0"}},
+		{"poetry", "~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"poetry", "1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"poetry", "*", []string{"0.1", "5.0"}, nil},
+		{"poetry", ">=1.0,<2.0", []string{"1.5"}, []string{"2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ecosystem+" "+tt.spec, func(t *testing.T) {
+			spec, err := testSpecParsers[tt.ecosystem](tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.allowed {
+				if !spec.allows(testVersion(t, tt.ecosystem, s)) {
+					t.Errorf("%s deveria aceitar %s", tt.spec, s)
+				}
+			}
+			for _, s := range tt.rejected {
+				if spec.allows(testVersion(t, tt.ecosystem, s)) {
+					t.Errorf("%s não deveria aceitar %s", tt.spec, s)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		spec      string
+	}{
+		{"npm", "latest"},
+		{"npm", "file:../lib"},
+		{"npm", "git+https://github.com/user/repo.git"},
+		{"npm", "https://example.com/pkg.tgz"},
+		{"npm", "workspace:*"},
+		{"npm", "1.2.3 - banana"},
+		{"cargo", "banana"},
+		{"cargo", "1.2, >=x.y"},
+		{"pypi", "banana"},
+		{"pypi", "~=1"},
+		{"pypi", "^1.2"},
+		{"pypi", "1.2.3"},
+		{"poetry", "^banana"},
+	}
+	for _, tt := range tests {
+		if _, err := testSpecParsers[tt.ecosystem](tt.spec); err == nil {
+			t.Errorf("%s: %q aceito, esperado erro", tt.ecosystem, tt.spec)
+		}
+	}
+}
+
+func TestVersionSpecRewrite(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		spec      string
+		resolved  string
+		defaultOp string
+		simple    bool
+		want      string
+	}{
+		{"npm", "^1.2.3", "1.9.0", "^", true, "^1.9.0"},
+		{"npm", "~1.2.3", "1.2.9", "^", true, "~1.2.9"},
+		{"npm", "~1.2", "1.2.5", "^", true, "~1.2"},
+		{"npm", "1.2.3", "1.2.3", "^", true, "1.2.3"},
+		{"npm", ">=1.0.0", "2.4.1", "^", true, ">=2.4.1"},
+		{"npm", "<2.0.0", "1.9.0", "^", false, "^1.9.0"},
+		{"npm", ">=1.0.0 <2.0.0", "1.9.0", "^", false, "^1.9.0"},
+		{"npm", "^1.0.0 || ^2.0.0", "2.1.0", "^", false, "^2.1.0"},
+		{"npm", "*", "3.0.0", "^", false, "^3.0.0"},
+		{"cargo", "1.2", "1.9.3", "", true, "1.9.3"},
+		{"cargo", "~1.2", "1.2.7", "", true, "~1.2"},
+		{"cargo", "=0.4.0", "0.4.0", "", true, "=0.4.0"},
+		{"cargo", ">=1.2, <1.5", "1.4.2", "", false, "1.4.2"},
+		{"pypi", "==1.2.0", "1.4.0", ">=", true, "==1.4.0"},
+		{"pypi", ">=2.0", "2.31.0", ">=", true, ">=2.31.0"},
+		{"pypi", "~=1.4", "1.9.2", ">=", true, "~=1.9"},
+		{"pypi", "~=1.4.2", "1.4.9", ">=", true, "~=1.4.9"},
+		{"pypi", ">=2.0,<3", "2.9", ">=", false, ">=2.9"},
+		{"pypi", "<3", "2.9", ">=", false, ">=2.9"},
+		{"pypi", "==1.4.*", "1.4.7", ">=", false, ">=1.4.7"},
+		{"poetry", "^1.2", "1.9.0", "^", true, "^1.9.0"},
+		{"poetry", "1.2.3", "1.4.0", "^", true, "1.4.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ecosystem+" "+tt.spec, func(t *testing.T) {
+			spec, err := testSpecParsers[tt.ecosystem](tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if spec.simple != tt.simple {
+				t.Errorf("simple = %v, esperado %v", spec.simple, tt.simple)
+			}
+			if got := spec.rewrite(testVersion(t, tt.ecosystem, tt.resolved), tt.defaultOp); got != tt.want {
+				t.Errorf("rewrite(%s) = %q, esperado %q", tt.resolved, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	var published []version
+	for _, s := range []string{"1.0.0", "1.2.0", "1.3.0-beta.1", "2.0.0", "2.1.0", "3.0.0-rc.1", "0.9.0"} {
+		published = append(published, testVersion(t, "npm", s))
+	}
+	tests := []struct {
+		spec     string
+		resolved string
+		widened  bool
+		ok       bool
+	}{
+		{"^1.0.0", "1.2.0", false, true},
+		{"~1.0.0", "1.0.0", false, true},
+		{"^2.0.0", "2.1.0", false, true},
+		{">=1.0.0 <2.0.0", "1.2.0", false, true},
+		{"*", "2.1.0", false, true},
+		{"^3.0.0-rc.1", "3.0.0-rc.1", false, true},
+		// Versão inventada: a mais recente da mesma versão major, sem pré-lançamentos
+		{"^1.5.0", "1.2.0", true, true},
+		{"^2.7.3", "2.1.0", true, true},
+		{"^3.1.0", "", false, false},
+		{"^4.0.0", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := parseNPMSpec(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved, widened, ok := resolveVersion(published, spec)
+			if resolved.raw != tt.resolved || widened != tt.widened || ok != tt.ok {
+				t.Errorf("resolveVersion = %q %v %v, esperado %q %v %v", resolved.raw, widened, ok, tt.resolved, tt.widened, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var addDir string
+var addYes bool
+var contextTokens int
+
+// addCmd define o comando "add".
+var addCmd = &cobra.Command{
+	Use:   "add <descrição da funcionalidade>",
+	Short: "Gera com IA uma nova funcionalidade em um projeto existente",
+	Long: `Resume a árvore e os arquivos do projeto (respeitando o .gitignore, o .zionignore e um
+orçamento de tokens que prioriza manifestos, pontos de entrada e configurações),
+pede ao modelo os arquivos novos e modificados para a funcionalidade descrita e
+mostra o diff de cada arquivo para revisão antes de aplicá-lo.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		startTime := time.Now()
+		feature := args[0]
+
+		fmt.Print(i18n.T("add.start"))
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+		fmt.Print(i18n.T("add.project", addDir))
+		fmt.Print(i18n.T("add.feature", feature))
+		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+		manifest, err := ai.GenerateFeature(addDir, feature, contextTokens)
+		if err != nil {
+			fmt.Print(i18n.T("add.error.generate", err))
+			os.Exit(1)
+		}
+
+		changes, err := ai.FeatureChanges(addDir, manifest)
+		if err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(changes) == 0 {
+			fmt.Print(i18n.T("add.no_changes"))
+			return
+		}
+
+		selected := reviewFileChanges(changes, addYes)
+		if len(selected) == 0 {
+			fmt.Print(i18n.T("add.none_applied"))
+			return
+		}
+		if err := ai.ApplyFileChanges(addDir, selected); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Print(i18n.T("add.done", len(selected), len(changes), time.Since(startTime).Seconds()))
+		printUsageSummary()
+	},
+}
+
+// reviewFileChanges mostra o diff de cada alteração e pergunta se deve ser aplicada.
+// Com yes todas as alterações são aceitas sem perguntar.
+func reviewFileChanges(changes []ai.FileChange, yes bool) []ai.FileChange {
+	fmt.Print(i18n.T("add.proposed", len(changes)))
+	for _, change := range changes {
+		fmt.Printf("   %s %s\n", changeSymbol(change.Kind), change.Path)
+	}
+	if yes {
+		return changes
+	}
+
+	fmt.Print(i18n.T("add.review.help"))
+	var selected []ai.FileChange
+	for i, change := range changes {
+		fmt.Printf("\n%s", change.Diff())
+		if change.Kind == ai.ChangeModified {
+			fmt.Print(i18n.T("add.review.overwrite", change.Path))
+		}
+		// As opções seguem a ordem aplicar/pular/todos/parar em qualquer idioma
+		switch askChoice(i18n.T("add.review.question", change.Path), i18n.T("add.review.options"), 1) {
+		case 0:
+			selected = append(selected, change)
+		case 2:
+			return append(selected, changes[i:]...)
+		case 3:
+			return selected
+		}
+	}
+	return selected
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addDir, "dir", ".", "Diretório do projeto existente")
+	addCmd.Flags().IntVar(&contextTokens, "context-tokens", ai.DefaultContextTokens, "Orçamento aproximado de tokens para o conteúdo do projeto enviado ao modelo")
+	addCmd.Flags().BoolVarP(&addYes, "yes", "y", false, "Aplica todas as alterações sem revisão")
+
+	rootCmd.AddCommand(addCmd)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var applyDir string
+var applyYes bool
+var applyFormat string
+
+// applyCmd define o comando "apply".
+var applyCmd = &cobra.Command{
+	Use:   "apply <manifesto>",
+	Short: "Cria ou atualiza um projeto a partir de um manifesto JSON, YAML ou TOML",
+	Long: `Lê um manifesto de projeto (gerado por zion export, por scaffold --manifest-out ou
+escrito à mão) e grava os diretórios e arquivos descritos nele. O formato vem da extensão
+(.json, .yaml, .yml, .toml) ou da flag --format; com "-" o manifesto é lido da entrada
+padrão. Arquivos que já existem com outro conteúdo são mostrados como diff para revisão,
+como em zion add, e arquivos que só existem no disco não são alterados.
+
+Arquivos binários vêm em base64, scripts podem ter o modo (como 0755), e entradas
+copy-from-template e url são copiadas do diretório do manifesto ou de ~/.zion/templates
+e baixadas antes da gravação.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		manifest, err := readManifestArg(args[0], applyFormat)
+		if err != nil {
+			fmt.Print(i18n.T("apply.error", err))
+			os.Exit(1)
+		}
+		fmt.Print(i18n.T("apply.start", args[0], len(manifest.Files), applyDir))
+
+		// Templates de copy-from-template são procurados também ao lado do manifesto
+		var templateDirs []string
+		if args[0] != "-" {
+			templateDirs = append(templateDirs, filepath.Dir(args[0]))
+		}
+		for _, problem := range manifest.Resolve(ai.TemplateDirs(templateDirs...)) {
+			fmt.Printf("   ⚠️  %v\n", problem)
+		}
+
+		for _, dir := range manifest.Directories {
+			if err := os.MkdirAll(filepath.Join(applyDir, filepath.FromSlash(dir)), 0755); err != nil {
+				fmt.Print(i18n.T("apply.error", err))
+				os.Exit(1)
+			}
+		}
+
+		changes, err := ai.ManifestChanges(applyDir, manifest)
+		if err != nil {
+			fmt.Print(i18n.T("apply.error", err))
+			os.Exit(1)
+		}
+		if len(changes) == 0 {
+			fmt.Print(i18n.T("apply.up_to_date"))
+			return
+		}
+
+		selected := reviewFileChanges(changes, applyYes)
+		if len(selected) == 0 {
+			fmt.Print(i18n.T("add.none_applied"))
+			return
+		}
+		if err := ai.ApplyFileChanges(applyDir, selected); err != nil {
+			fmt.Print(i18n.T("apply.error", err))
+			os.Exit(1)
+		}
+		fmt.Print(i18n.T("apply.done", len(selected), len(changes)))
+	},
+}
+
+// readManifestArg lê o manifesto de um arquivo ou, com "-", da entrada padrão
+func readManifestArg(file, format string) (*ai.ProjectManifest, error) {
+	if file != "-" {
+		return ai.ReadManifestFile(file, format)
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto da entrada padrão: %v", err)
+	}
+	return ai.DecodeManifest(data, format)
+}
+
+func init() {
+	applyCmd.Flags().StringVar(&applyDir, "dir", ".", "Diretório onde o projeto é criado ou atualizado")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Aplica todas as alterações sem revisão")
+	applyCmd.Flags().StringVar(&applyFormat, "format", "", "Formato do manifesto (json, yaml, toml); por padrão vem da extensão")
+
+	rootCmd.AddCommand(applyCmd)
+}
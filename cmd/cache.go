@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd agrupa os comandos de manutenção do cache de respostas.
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Gerencia o cache de respostas da IA",
+}
+
+// cacheClearCmd define o comando "cache clear".
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove todas as respostas armazenadas em cache",
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := ai.ClearCache()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(i18n.T("cache.cleared", removed))
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// commandResult é o resultado de um comando executado no projeto
+type commandResult struct {
+	Command string
+	Output  string
+	Err     error
+	Skipped bool
+}
+
+// lookupProjectCommand procura o executável do comando, primeiro em node_modules/.bin do projeto.
+// Serve apenas para ferramentas do projeto, como tsc e eslint; git e os gerenciadores de
+// pacotes usam lookupSystemCommand.
+func lookupProjectCommand(dir, name string) (string, bool) {
+	if local, ok := lookupInstalledBinary(dir, name); ok {
+		return local, true
+	}
+	return lookupSystemCommand(name)
+}
+
+// lookupInstalledBinary procura o executável em node_modules/.bin, aceitando só os links
+// criados pelo gerenciador de pacotes, que apontam para dentro de node_modules. O Zion grava
+// apenas arquivos comuns, então um executável que veio dos arquivos gerados pelo modelo
+// nunca é usado.
+func lookupInstalledBinary(dir, name string) (string, bool) {
+	local := filepath.Join(dir, "node_modules", ".bin", name)
+	info, err := os.Lstat(local)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	target, err := filepath.EvalSymlinks(local)
+	if err != nil {
+		return "", false
+	}
+	modules, err := filepath.EvalSymlinks(filepath.Join(dir, "node_modules"))
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(modules, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return local, true
+}
+
+// lookupSystemCommand procura o executável do comando apenas no PATH
+func lookupSystemCommand(name string) (string, bool) {
+	path, err := exec.LookPath(name)
+	return path, err == nil
+}
+
+// runProjectCommand executa um comando no diretório do projeto e captura a saída.
+// Comandos cujo executável não está instalado são pulados.
+func runProjectCommand(dir string, timeout time.Duration, name string, args ...string) commandResult {
+	path, ok := lookupProjectCommand(dir, name)
+	return runCommand(dir, timeout, path, ok, name, args)
+}
+
+// runSystemCommand executa no diretório do projeto um comando procurado apenas no PATH,
+// como git ou o gerenciador de pacotes
+func runSystemCommand(dir string, timeout time.Duration, name string, args ...string) commandResult {
+	path, ok := lookupSystemCommand(name)
+	return runCommand(dir, timeout, path, ok, name, args)
+}
+
+// runCommand executa o executável em path, já localizado, e captura a saída; found falso
+// indica que o comando não está instalado e é pulado
+func runCommand(dir string, timeout time.Duration, path string, found bool, name string, args []string) commandResult {
+	result := commandResult{Command: strings.Join(append([]string{name}, args...), " ")}
+	if !found {
+		result.Skipped = true
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	result.Err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		result.Err = fmt.Errorf("tempo limite de %v excedido", timeout)
+	}
+	result.Output = strings.TrimSpace(output.String())
+	return result
+}
+
+// runCommandLine executa uma linha de comando declarada em um pacote de linguagem.
+// Os argumentos são separados por espaços, sem interpretação de um shell. Com system,
+// o executável é procurado apenas no PATH.
+func runCommandLine(dir, command string, timeout time.Duration, system bool) commandResult {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return commandResult{Command: command, Skipped: true}
+	}
+	if system {
+		return runSystemCommand(dir, timeout, fields[0], fields[1:]...)
+	}
+	return runProjectCommand(dir, timeout, fields[0], fields[1:]...)
+}
+
+// tailLines retorna as últimas n linhas não vazias de um texto
+func tailLines(text string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"zion/ai"
+	"zion/i18n"
+	"zion/plugins"
+
+	"github.com/spf13/cobra"
+)
+
+var diffStat bool
+var diffJSON bool
+var diffBase bool
+var diffContextTokens int
+var diffWorkers int
+
+// diffStatWidth é a largura máxima do histograma de +/- do --stat
+const diffStatWidth = 40
+
+// fileDiff é um arquivo na saída --json do comando diff
+type fileDiff struct {
+	Path      string `json:"path"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// diffCmd define o comando "diff".
+var diffCmd = &cobra.Command{
+	Use:   "diff [diretório]",
+	Short: "Mostra o quanto um projeto se afastou do que o Zion geraria hoje",
+	Long: `Gera o projeto de novo com as entradas registradas em .zion/lock.json e mostra o diff
+unificado de cada arquivo entre a nova geração e o projeto: arquivos adicionados e
+modificados são os que o projeto tem a mais ou diferentes, e removidos são os que a
+geração tem e o projeto não. Com --base, a comparação é feita com o conteúdo gerado
+originalmente (.zion/base.json), sem chamar o modelo.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		// Com --json, as mensagens da geração vão para a saída de erro
+		stdout := os.Stdout
+		if diffJSON {
+			os.Stdout = os.Stderr
+		}
+		generated, err := generatedManifest(dir)
+		os.Stdout = stdout
+		if err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("diff.error", err))
+			os.Exit(1)
+		}
+
+		changes, err := ai.DiffProject(dir, generated)
+		if err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("diff.error", err))
+			os.Exit(1)
+		}
+
+		switch {
+		case diffJSON:
+			printDiffJSON(changes)
+		case len(changes) == 0:
+			fmt.Print(i18n.T("diff.none"))
+		case diffStat:
+			printDiffStat(changes)
+		default:
+			for _, change := range changes {
+				fmt.Print(change.Diff())
+			}
+		}
+	},
+}
+
+// generatedManifest retorna o conteúdo gerado originalmente (--base) ou uma nova geração
+// com as entradas do lock do projeto, já com as etapas pós-criação que alteram arquivos
+func generatedManifest(dir string) (*ai.ProjectManifest, error) {
+	if diffBase {
+		return ai.LoadBaseManifest(dir)
+	}
+	lock, err := ai.LoadScaffoldLock(dir)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Print(i18n.T("diff.regenerating", dir, lock.Inputs.Language))
+	next, err := ai.RegenerateScaffold(lock, ai.RegenerateOptions{
+		Plugins:       plugins.ListPlugins(),
+		ContextTokens: diffContextTokens,
+		Workers:       diffWorkers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return postCreateFromLock(lock, next)
+}
+
+// printDiffStat mostra uma linha por arquivo com as linhas adicionadas e removidas, como git diff --stat
+func printDiffStat(changes []ai.FileChange) {
+	width, largest := 0, 0
+	for _, change := range changes {
+		if len(change.Path) > width {
+			width = len(change.Path)
+		}
+		if added, removed := change.Stat(); added+removed > largest {
+			largest = added + removed
+		}
+	}
+
+	totalAdded, totalRemoved := 0, 0
+	for _, change := range changes {
+		added, removed := change.Stat()
+		totalAdded += added
+		totalRemoved += removed
+		plus, minus := added, removed
+		if largest > diffStatWidth {
+			plus = (added*diffStatWidth + largest - 1) / largest
+			minus = (removed*diffStatWidth + largest - 1) / largest
+		}
+		fmt.Printf(" %-*s | %5d %s%s\n", width, change.Path, added+removed, strings.Repeat("+", plus), strings.Repeat("-", minus))
+	}
+	fmt.Print(i18n.T("diff.stat_summary", len(changes), totalAdded, totalRemoved))
+}
+
+// printDiffJSON escreve as alterações em JSON; com --stat, sem o texto do diff
+func printDiffJSON(changes []ai.FileChange) {
+	files := make([]fileDiff, 0, len(changes))
+	for _, change := range changes {
+		added, removed := change.Stat()
+		file := fileDiff{Path: change.Path, Status: change.Kind, Additions: added, Deletions: removed}
+		if !diffStat {
+			file.Diff = change.Diff()
+		}
+		files = append(files, file)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(files); err != nil {
+		fmt.Fprint(os.Stderr, i18n.T("diff.error", err))
+		os.Exit(1)
+	}
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffStat, "stat", false, "Mostra apenas as linhas adicionadas e removidas por arquivo")
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Escreve o resultado em JSON")
+	diffCmd.Flags().BoolVar(&diffBase, "base", false, "Compara com o conteúdo gerado originalmente, sem chamar o modelo")
+	diffCmd.Flags().IntVar(&diffContextTokens, "context-tokens", ai.DefaultContextTokens, "Orçamento aproximado de tokens do projeto de referência registrado no lock")
+	diffCmd.Flags().IntVar(&diffWorkers, "workers", ai.DefaultOutlineWorkers, "Número máximo de requisições simultâneas para projetos gerados em multi-etapas")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var exportOutput string
+var exportFormat string
+
+// exportCmd define o comando "export".
+var exportCmd = &cobra.Command{
+	Use:   "export [diretório]",
+	Short: "Gera o manifesto JSON, YAML ou TOML de um projeto existente",
+	Long: `Lê os diretórios e arquivos do projeto (respeitando o .gitignore e o .zionignore) e
+grava um manifesto com o conteúdo completo de cada arquivo e sua soma sha256, que pode
+ser recriado com zion apply. Sem --output o manifesto é escrito na saída padrão.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		manifest, err := ai.ReadManifest(dir)
+		if err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("export.error", err))
+			os.Exit(1)
+		}
+		if err := writeManifest(manifest, exportOutput, exportFormat); err != nil {
+			fmt.Fprint(os.Stderr, i18n.T("export.error", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// writeManifest grava o manifesto em file ou, sem arquivo, na saída padrão (JSON por padrão)
+func writeManifest(manifest *ai.ProjectManifest, file, format string) error {
+	if file != "" && file != "-" {
+		if err := ai.WriteManifestFile(file, format, manifest); err != nil {
+			return err
+		}
+		fmt.Fprint(os.Stderr, i18n.T("export.done", len(manifest.Files), file))
+		return nil
+	}
+	if format == "" {
+		format = ai.ManifestJSON
+	}
+	data, err := manifest.Encode(format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Arquivo do manifesto (.json, .yaml, .yml ou .toml)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Formato do manifesto (json, yaml, toml); por padrão vem da extensão")
+
+	rootCmd.AddCommand(exportCmd)
+}
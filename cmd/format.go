@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// formatTimeout limita o tempo de cada formatador externo
+const formatTimeout = 5 * time.Minute
+
+var formatDryRun bool
+var formatNoExternal bool
+
+// formatCmd define o comando "format".
+var formatCmd = &cobra.Command{
+	Use:   "format [diretório]",
+	Short: "Formata os arquivos do projeto",
+	Long: `Formata os arquivos de texto do projeto: Go com go/format, JSON com a indentação de
+json_style no config.yaml, YAML com indentação uniforme (indent_size do .editorconfig, ou 2)
+e, em todos os arquivos, os espaços no fim das linhas, a quebra de linha final e o fim de
+linha definidos no .editorconfig do projeto.
+
+Formatadores externos (prettier, black, rustfmt) são executados apenas se listados em
+formatters no config.yaml e instalados; o prettier é procurado também em node_modules/.bin,
+entre os links criados pelo gerenciador de pacotes.
+Arquivos que um formatador não consegue processar, como um arquivo Go com erro de sintaxe,
+ficam como estavam e são apontados no relatório.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		report := runFormatting(dir, formatDryRun, !formatNoExternal)
+		if report == nil || len(report.Problems()) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// runFormatting formata o projeto em dir e mostra os arquivos alterados
+func runFormatting(dir string, dryRun, external bool) *ai.FormatReport {
+	fmt.Print(i18n.T("format.start"))
+	report, err := ai.FormatProject(dir, dryRun)
+	if err != nil {
+		fmt.Print(i18n.T("format.error", err))
+		return nil
+	}
+	if external {
+		formatters, err := ai.ConfiguredFormatters()
+		if err != nil {
+			fmt.Print(i18n.T("format.error", err))
+		} else if err := runExternalFormatters(dir, formatters, dryRun, report); err != nil {
+			fmt.Print(i18n.T("format.error", err))
+		}
+		report.Sort()
+	}
+	printFormatReport(report)
+	if dryRun && len(report.Changed()) > 0 {
+		fmt.Print(i18n.T("format.dry_run"))
+	}
+	return report
+}
+
+// runExternalFormatters executa cada formatador externo sobre os arquivos que ele trata,
+// registrando no relatório os arquivos que mudaram
+func runExternalFormatters(dir string, formatters []ai.ExternalFormatter, dryRun bool, report *ai.FormatReport) error {
+	if len(formatters) == 0 {
+		return nil
+	}
+	manifest, err := ai.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	contents := manifest.FileMap()
+
+	for _, formatter := range formatters {
+		var files []string
+		for _, file := range manifest.Files {
+			if _, ok := contents[file.Path]; ok && formatter.Handles(file.Path) {
+				files = append(files, file.Path)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+		if dryRun {
+			fmt.Print(i18n.T("format.external_dry_run", formatter.Name, len(files)))
+			continue
+		}
+
+		if _, ok := lookupProjectCommand(dir, formatter.Name); !ok {
+			fmt.Print(i18n.T("format.external_missing", formatter.Name))
+			continue
+		}
+
+		args := append([]string(nil), formatter.Args...)
+		for _, file := range files {
+			args = append(args, filepath.FromSlash(file))
+		}
+		fmt.Print(i18n.T("format.external_running", formatter.Name, len(files)))
+		result := runProjectCommand(dir, formatTimeout, formatter.Name, args...)
+		if result.Err != nil {
+			// O formatador pode ter alterado parte dos arquivos antes de falhar
+			fmt.Print(i18n.T("format.external_failed", formatter.Name, result.Err))
+			for _, line := range tailLines(result.Output, 5) {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+			if err != nil || string(data) == contents[file] {
+				continue
+			}
+			report.AddChange(file, formatter.Name)
+			contents[file] = string(data)
+		}
+	}
+	return nil
+}
+
+// printFormatReport mostra os arquivos alterados, os que os formatadores recusaram e um resumo
+func printFormatReport(report *ai.FormatReport) {
+	changed, problems := report.Changed(), report.Problems()
+	if len(changed) == 0 && len(problems) == 0 {
+		fmt.Print(i18n.T("format.none"))
+		return
+	}
+	for _, file := range changed {
+		fmt.Printf("   🎨 %s (%s)\n", file.Path, strings.Join(file.Formatters, ", "))
+	}
+	for _, file := range problems {
+		fmt.Printf("   ⚠️  %s: %s\n", file.Path, file.Problem)
+	}
+	fmt.Print(i18n.T("format.summary", len(changed), len(problems)))
+}
+
+func init() {
+	formatCmd.Flags().BoolVar(&formatDryRun, "dry-run", false, "Mostra os arquivos que seriam alterados sem gravá-los")
+	formatCmd.Flags().BoolVar(&formatNoExternal, "no-external", false, "Não executa os formatadores externos configurados")
+
+	rootCmd.AddCommand(formatCmd)
+}
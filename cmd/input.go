@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"zion/i18n"
+)
+
+// stdinReader é compartilhado para que respostas digitadas em sequência não se percam
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// askConfirmation faz uma pergunta de sim/não no terminal; a resposta padrão é "não"
+func askConfirmation(question string) bool {
+	fmt.Printf("%s %s: ", question, i18n.T("input.yes_no"))
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "s" || answer == "sim" || answer == "y" || answer == "yes"
+}
+
+// askChoice pede ao usuário uma das opções informadas (ex: "s/n/t/q") e retorna o
+// índice da opção escolhida; respostas inválidas ou vazias retornam defaultIndex
+func askChoice(question, options string, defaultIndex int) int {
+	fmt.Printf("%s [%s]: ", question, options)
+	answer, err := stdinReader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if err != nil && answer == "" {
+		return defaultIndex
+	}
+	for i, option := range strings.Split(options, "/") {
+		if answer == option {
+			return i
+		}
+	}
+	return defaultIndex
+}
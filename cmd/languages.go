@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"zion/i18n"
+	"zion/languages"
+
+	"github.com/spf13/cobra"
+)
+
+var languagesVerbose bool
+
+// languagesCmd define o comando "languages".
+var languagesCmd = &cobra.Command{
+	Use:   "languages",
+	Short: "Lista as linguagens e frameworks disponíveis para o scaffold",
+	Long: `Lista os pacotes de linguagem disponíveis. Pacotes em JSON colocados em
+~/.zion/languages ou em .zion/languages no diretório atual acrescentam linguagens
+ou substituem as padrão com o mesmo nome.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registry, err := languages.Load(".")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("languages.header"))
+		for _, pack := range registry.Packs() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pack.Name, listOrDash(pack.Aliases), listOrDash(pack.FrameworkNames()), pack.Origin)
+		}
+		w.Flush()
+
+		if !languagesVerbose {
+			fmt.Print(i18n.T("languages.verbose_hint"))
+			return
+		}
+		for _, pack := range registry.Packs() {
+			fmt.Printf("\n🔧 %s\n", pack.DisplayName)
+			fmt.Print(i18n.T("languages.expected_files", listOrDash(pack.ExpectedFiles)))
+			fmt.Print(i18n.T("languages.post_create", listOrDash(pack.PostCreate)))
+			fmt.Print(i18n.T("languages.validators", listOrDash(pack.Validators)))
+			fmt.Print(i18n.T("languages.verify", listOrDash(pack.Verify)))
+			for _, framework := range pack.Frameworks {
+				fmt.Printf("   ├── 🧩 %s (%s)\n", framework.DisplayName, framework.Name)
+			}
+		}
+	},
+}
+
+// listOrDash junta os itens com vírgulas ou retorna "-" quando a lista está vazia
+func listOrDash(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ", ")
+}
+
+func init() {
+	languagesCmd.Flags().BoolVarP(&languagesVerbose, "verbose", "v", false, "Mostra os detalhes de cada pacote")
+
+	rootCmd.AddCommand(languagesCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var pinRegistries map[string]string
+var pinDryRun bool
+
+// pinCmd define o comando "pin".
+var pinCmd = &cobra.Command{
+	Use:   "pin [diretório]",
+	Short: "Fixa as dependências na versão publicada mais recente compatível",
+	Long: `Consulta o registro de cada ecossistema e reescreve as dependências de package.json,
+go.mod, requirements*.txt, pyproject.toml e Cargo.toml para a versão publicada mais
+recente compatível com a especificação. Versões que não existem no registro são trocadas
+pela mais recente com a mesma versão major, e pacotes desconhecidos são apontados.
+
+Os registros padrão são os públicos; em config.yaml, a seção registries (ou a flag
+--registry) aponta cada ecossistema (npm, go, pypi, cargo) para um espelho local, como
+Verdaccio, um GOPROXY ou devpi, ou para um diretório com a mesma estrutura do registro.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		report := runPinning(dir, pinRegistries, pinDryRun)
+		if report == nil || len(report.Problems()) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// runPinning fixa as versões das dependências do projeto em dir e mostra o resultado
+func runPinning(dir string, registries map[string]string, dryRun bool) *ai.PinReport {
+	fmt.Print(i18n.T("pin.start"))
+	report, err := ai.PinDependencies(dir, registries, dryRun)
+	if err != nil {
+		fmt.Print(i18n.T("pin.error", err))
+		return nil
+	}
+	printPinReport(report)
+	if dryRun && len(report.Manifests) > 0 {
+		fmt.Print(i18n.T("pin.dry_run"))
+	}
+	return report
+}
+
+// printPinReport mostra as dependências alteradas, as que não puderam ser resolvidas e um resumo
+func printPinReport(report *ai.PinReport) {
+	if len(report.Dependencies) == 0 {
+		fmt.Print(i18n.T("pin.none"))
+		return
+	}
+	changed, problems := report.Changed(), report.Problems()
+	for _, dep := range changed {
+		fmt.Printf("   📌 %s: %s %s → %s", dep.Manifest, dep.Name, dep.From, dep.To)
+		if dep.Widened {
+			fmt.Print(i18n.T("pin.widened"))
+		}
+		fmt.Println()
+	}
+	for _, dep := range problems {
+		if dep.Name == "" {
+			fmt.Printf("   ⚠️  %s: %s\n", dep.Manifest, dep.Problem)
+		} else {
+			fmt.Printf("   ⚠️  %s: %s: %s\n", dep.Manifest, dep.Name, dep.Problem)
+		}
+	}
+	fmt.Print(i18n.T("pin.summary", len(report.Dependencies), len(changed), len(problems)))
+}
+
+func init() {
+	pinCmd.Flags().StringToStringVar(&pinRegistries, "registry", nil, "Registro de um ecossistema, como npm=http://localhost:4873 ou go=./index (pode ser repetida)")
+	pinCmd.Flags().BoolVar(&pinDryRun, "dry-run", false, "Mostra as alterações sem gravar os manifestos")
+
+	rootCmd.AddCommand(pinCmd)
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"zion/ai"
+	"zion/i18n"
+	"zion/languages"
+)
+
+// installTimeout limita o tempo de cada comando de instalação de dependências
+const installTimeout = 10 * time.Minute
+
+// gitTimeout limita o tempo de cada comando git
+const gitTimeout = time.Minute
+
+// Situação de uma etapa pós-criação
+const (
+	stepDone = iota
+	stepSkipped
+	stepFailed
+)
+
+// postCreateStep é o resultado de uma etapa pós-criação, exibido no resumo do scaffold
+type postCreateStep struct {
+	Name   string
+	Status int
+	// Detail é o motivo de uma etapa pulada ou a saída de uma etapa que falhou
+	Detail string
+}
+
+// writeGitignore cria o .gitignore com os padrões do pacote ou acrescenta ao .gitignore
+// gerado os padrões que ainda não estão nele
+func writeGitignore(dir string, patterns []string) postCreateStep {
+	step := postCreateStep{Name: ".gitignore"}
+	if len(patterns) == 0 {
+		step.Status, step.Detail = stepSkipped, i18n.T("postcreate.reason.no_patterns")
+		return step
+	}
+
+	path := filepath.Join(dir, ".gitignore")
+	existing := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		step.Status, step.Detail = stepFailed, err.Error()
+		return step
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		existing[strings.TrimSpace(scanner.Text())] = true
+	}
+
+	var missing []string
+	for _, pattern := range patterns {
+		if !existing[pattern] {
+			missing = append(missing, pattern)
+		}
+	}
+	if len(missing) == 0 {
+		step.Status, step.Detail = stepSkipped, i18n.T("postcreate.reason.up_to_date")
+		return step
+	}
+
+	content := string(data)
+	if content != "" {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n" + i18n.T("postcreate.gitignore_header") + "\n"
+	}
+	content += strings.Join(missing, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		step.Status, step.Detail = stepFailed, err.Error()
+		return step
+	}
+	step.Name = i18n.T("postcreate.gitignore", len(missing))
+	return step
+}
+
+// runInstall executa os comandos de instalação de dependências do pacote. O gerenciador de
+// pacotes é procurado apenas no PATH, nunca entre os arquivos gerados.
+func runInstall(dir string, commands []string) []postCreateStep {
+	var steps []postCreateStep
+	for _, command := range commands {
+		fmt.Print(i18n.T("postcreate.running", command))
+		result := runCommandLine(dir, command, installTimeout, true)
+		steps = append(steps, commandStep(result, i18n.T("postcreate.reason.not_installed")))
+	}
+	return steps
+}
+
+// initGitRepository cria o repositório git do projeto com um commit inicial. Do diretório
+// .zion, só o lock.json entra no commit; a conversa de refinamento e a base do zion update
+// ficam de fora. Projetos criados dentro de um repositório existente não ganham um
+// repositório próprio.
+func initGitRepository(dir string) postCreateStep {
+	step := postCreateStep{Name: i18n.T("postcreate.git")}
+	if inside := runSystemCommand(dir, gitTimeout, "git", "rev-parse", "--is-inside-work-tree"); inside.Skipped {
+		step.Status, step.Detail = stepSkipped, i18n.T("postcreate.reason.not_installed")
+		return step
+	} else if inside.Err == nil && inside.Output == "true" {
+		step.Status, step.Detail = stepSkipped, i18n.T("postcreate.reason.inside_repository")
+		return step
+	}
+
+	commands := [][]string{
+		{"init", "-q"},
+		{"add", "-A", "--", ".", ":(exclude).zion"},
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".zion", "lock.json")); err == nil {
+		commands = append(commands, []string{"add", "--", ".zion/lock.json"})
+	}
+	commands = append(commands, []string{"commit", "-q", "-m", i18n.T("postcreate.commit_message")})
+	for _, args := range commands {
+		result := runSystemCommand(dir, gitTimeout, "git", args...)
+		if result.Err != nil {
+			failed := commandStep(result, "")
+			failed.Name = fmt.Sprintf("%s (git %s)", step.Name, args[0])
+			return failed
+		}
+	}
+	return step
+}
+
+// commandStep converte o resultado de um comando em uma etapa pós-criação
+func commandStep(result commandResult, skippedReason string) postCreateStep {
+	step := postCreateStep{Name: result.Command}
+	switch {
+	case result.Skipped:
+		step.Status, step.Detail = stepSkipped, skippedReason
+	case result.Err != nil:
+		step.Status = stepFailed
+		step.Detail = fmt.Sprintf("%v", result.Err)
+		if lines := tailLines(result.Output, 5); len(lines) > 0 {
+			step.Detail += "\n" + strings.Join(lines, "\n")
+		}
+	}
+	return step
+}
+
+// installedCommands retorna os comandos de instalação que foram executados com sucesso
+func installedCommands(steps []postCreateStep) map[string]bool {
+	installed := make(map[string]bool)
+	for _, step := range steps {
+		if step.Status == stepDone {
+			installed[step.Name] = true
+		}
+	}
+	return installed
+}
+
+// printPostCreateSteps mostra no resumo o resultado de cada etapa pós-criação
+func printPostCreateSteps(steps []postCreateStep) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Print(i18n.T("postcreate.header"))
+	for _, step := range steps {
+		switch step.Status {
+		case stepDone:
+			fmt.Printf("   ✅ %s\n", step.Name)
+		case stepSkipped:
+			fmt.Printf("   ⏭️  %s (%s)\n", step.Name, step.Detail)
+		case stepFailed:
+			lines := strings.Split(step.Detail, "\n")
+			fmt.Printf("   ❌ %s: %s\n", step.Name, lines[0])
+			for _, line := range lines[1:] {
+				fmt.Printf("      │ %s\n", line)
+			}
+		}
+	}
+}
+
+// postCreateManifest aplica a um manifesto gerado, em um diretório temporário, as etapas
+// pós-criação que alteram os arquivos de forma previsível (pin, .gitignore e formatação),
+// na ordem do scaffold. O zion update compara a nova geração com a base nessas mesmas
+// condições, para que as alterações do próprio Zion não sejam tratadas como locais.
+func postCreateManifest(m *ai.ProjectManifest, steps []string, pack *languages.Pack, fw *languages.Framework, registries map[string]string) (*ai.ProjectManifest, error) {
+	enabled := make(map[string]bool)
+	for _, step := range steps {
+		enabled[step] = true
+	}
+	if !enabled["pin"] && !enabled["gitignore"] && !enabled["format"] {
+		return m, nil
+	}
+
+	dir, err := os.MkdirTemp("", "zion-postcreate-")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório temporário: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ai.WriteManifestTree(dir, m); err != nil {
+		return nil, err
+	}
+
+	if enabled["pin"] {
+		if _, err := ai.PinDependencies(dir, registries, false); err != nil {
+			return nil, err
+		}
+	}
+	if enabled["gitignore"] && pack != nil {
+		if step := writeGitignore(dir, pack.GitignoreFor(fw)); step.Status == stepFailed {
+			return nil, fmt.Errorf("erro ao gravar .gitignore: %s", step.Detail)
+		}
+	}
+	if enabled["format"] {
+		report, err := ai.FormatProject(dir, false)
+		if err != nil {
+			return nil, err
+		}
+		formatters, err := ai.ConfiguredFormatters()
+		if err != nil {
+			return nil, err
+		}
+		if err := runExternalFormatters(dir, formatters, false, report); err != nil {
+			return nil, err
+		}
+	}
+	return ai.RefreshManifest(dir, m)
+}
+
+// postCreateFromLock aplica a uma nova geração as etapas pós-criação registradas no lock,
+// com os mesmos registros de --registry usados no scaffold
+func postCreateFromLock(lock *ai.ScaffoldLock, next *ai.ProjectManifest) (*ai.ProjectManifest, error) {
+	pack, fw, err := resolveLanguagePack(lock.Inputs.Language, lock.Inputs.Framework)
+	if err != nil {
+		return nil, err
+	}
+	return postCreateManifest(next, lock.Inputs.Steps, pack, fw, lock.Inputs.Registries)
+}
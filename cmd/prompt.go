@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"zion/ai"
+	"zion/i18n"
+	"zion/prompts"
+
+	"github.com/spf13/cobra"
+)
+
+var promptName string
+var promptDescription string
+var promptFramework string
+
+// promptCmd agrupa os comandos de inspeção dos templates de prompt.
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Inspeciona os templates de prompt usados na geração",
+	Long: `Os prompts são templates (text/template) embutidos no Zion, listados por 'zion prompt list'.
+Para ajustá-los sem recompilar, crie um arquivo com o mesmo nome em ~/.zion/prompts ou em
+.zion/prompts no diretório atual (ex: .zion/prompts/scaffold.tmpl).
+
+Os requisitos de cada linguagem e framework vêm dos pacotes de linguagem (ex: go.json), que
+podem ser substituídos por um pacote com o mesmo nome em ~/.zion/languages ou
+.zion/languages; veja 'zion languages'.`,
+}
+
+// promptShowCmd exibe o prompt completo de scaffold para uma linguagem.
+var promptShowCmd = &cobra.Command{
+	Use:   "show <linguagem>",
+	Short: "Mostra o prompt de scaffold renderizado para uma linguagem",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prompt, err := ai.ScaffoldPrompt(args[0], promptFramework, promptName, promptDescription)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(prompt)
+	},
+}
+
+// promptListCmd lista os templates disponíveis e de onde cada um foi carregado.
+var promptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lista os templates de prompt e a origem de cada um",
+	Run: func(cmd *cobra.Command, args []string) {
+		set, err := prompts.Load(".")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(i18n.T("prompt.list_header"))
+		for _, name := range set.Names() {
+			fmt.Printf("   ├── %-28s %s\n", name, set.Origin(name))
+		}
+	},
+}
+
+func init() {
+	promptShowCmd.Flags().StringVarP(&promptName, "name", "n", "meu-projeto", "Nome do projeto usado na renderização")
+	promptShowCmd.Flags().StringVarP(&promptDescription, "description", "d", "", "Descrição usada na renderização")
+
+	promptShowCmd.Flags().StringVarP(&promptFramework, "framework", "f", "", "Framework usado na renderização")
+
+	promptCmd.AddCommand(promptShowCmd)
+	promptCmd.AddCommand(promptListCmd)
+	rootCmd.AddCommand(promptCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+// refineCmd define o comando "refine".
+var refineCmd = &cobra.Command{
+	Use:   "refine <dir>",
+	Short: "Refina um projeto existente conversando com a IA",
+	Long: `Abre uma conversa com a IA sobre um projeto já gerado. Cada instrução
+(ex: "adicione Docker", "use Fastify em vez de Express") gera um novo manifesto,
+cujas diferenças são exibidas e aplicadas somente após confirmação.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := args[0]
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			fmt.Print(i18n.T("refine.dir_not_found", dir))
+			os.Exit(1)
+		}
+
+		conv, err := ai.ConversationFromProject(dir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		runRefinementLoop(dir, conv)
+		printUsageSummary()
+	},
+}
+
+// runRefinementLoop lê instruções do usuário até uma linha vazia, mostrando as
+// diferenças de cada novo manifesto e aplicando-as quando confirmadas
+func runRefinementLoop(dir string, conv *ai.Conversation) {
+	fmt.Print(i18n.T("refine.intro"))
+	for {
+		fmt.Print(i18n.T("refine.ask"))
+		line, err := stdinReader.ReadString('\n')
+		instruction := strings.TrimSpace(line)
+		if instruction == "" {
+			break
+		}
+
+		manifest, refineErr := conv.Refine(instruction)
+		if refineErr != nil {
+			fmt.Print(i18n.T("refine.error", refineErr))
+			if err != nil {
+				break
+			}
+			continue
+		}
+
+		changes, diffErr := ai.DiffManifests(conv.Manifest(), manifest)
+		if diffErr != nil {
+			fmt.Printf("❌ %v\n", diffErr)
+			continue
+		}
+		if len(changes) == 0 {
+			fmt.Print(i18n.T("refine.no_changes"))
+			continue
+		}
+
+		printFileChanges(changes)
+		if !askConfirmation(i18n.T("refine.confirm")) {
+			fmt.Print(i18n.T("refine.discarded"))
+			continue
+		}
+
+		if err := ai.ApplyFileChanges(dir, changes); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		// A conversa guarda os arquivos gravados, já sem os segredos trocados na verificação
+		if applied, readErr := ai.ReadProjectManifest(dir); readErr == nil {
+			manifest = applied
+		}
+		if err := conv.Accept(instruction, manifest); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		} else if err := conv.Save(dir); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		fmt.Print(i18n.T("refine.applied"))
+
+		if err != nil {
+			break
+		}
+	}
+}
+
+// printFileChanges exibe um resumo das alterações seguido do diff de cada arquivo
+func printFileChanges(changes []ai.FileChange) {
+	fmt.Print(i18n.T("refine.changed", len(changes)))
+	for _, change := range changes {
+		fmt.Printf("   %s %s\n", changeSymbol(change.Kind), change.Path)
+	}
+	for _, change := range changes {
+		fmt.Printf("\n%s", change.Diff())
+	}
+}
+
+// changeSymbol retorna o símbolo usado nas listas de alterações
+func changeSymbol(kind string) string {
+	switch kind {
+	case ai.ChangeAdded:
+		return "+"
+	case ai.ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(refineCmd)
+}
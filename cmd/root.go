@@ -5,10 +5,16 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"zion/ai"
 	"zion/config"
+	"zion/i18n"
 	"zion/plugins"
 )
 
+var noCache bool
+var uiLang string
+var docLang string
+
 // rootCmd é o comando principal da CLI.
 var rootCmd = &cobra.Command{
 	Use:   "zion",
@@ -16,6 +22,24 @@ var rootCmd = &cobra.Command{
 	Long: `Zion é uma ferramenta de scaffolding que gera a estrutura
 de projetos para qualquer linguagem, integrando-se com serviços de AI (GPT/Gemini)
 e reforçando boas práticas de código. Além disso, possui um sistema de plugins para extensão.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		ai.SetCacheEnabled(!noCache)
+
+		// Flags têm precedência sobre config.yaml e as variáveis ZION_LANG/ZION_DOC_LANG
+		cfg := config.LoadConfig()
+		if uiLang == "" {
+			uiLang = cfg.Lang
+		}
+		if uiLang != "" {
+			if err := i18n.SetLanguage(uiLang); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+		if docLang == "" {
+			docLang = cfg.DocLang
+		}
+		ai.SetDocLanguage(docLang)
+	},
 }
 
 // Execute inicia a CLI.
@@ -25,7 +49,7 @@ func Execute() {
 	
 	// Carregar plugins
 	if err := plugins.LoadPlugins(cfg); err != nil {
-		fmt.Printf("Erro ao carregar plugins: %v\n", err)
+		fmt.Print(i18n.T("root.plugins_error", err))
 	}
 	
 	// Executar o comando raiz
@@ -35,3 +59,8 @@ func Execute() {
 	}
 }
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&uiLang, "lang", "", "Idioma das mensagens da CLI (pt, en)")
+	rootCmd.PersistentFlags().StringVar(&docLang, "doc-lang", "", "Idioma da documentação e dos comentários gerados (padrão: o mesmo da CLI)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Ignora o cache de respostas e sempre chama a API")
+}
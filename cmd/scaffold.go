@@ -1,18 +1,51 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 	"zion/ai"
+	"zion/i18n"
+	"zion/languages"
 	"zion/plugins"
 
 	"github.com/spf13/cobra"
 )
 
 var language string
+var framework string
 var projectName string
 var description string
+var stream bool
+var writeEarly bool
+var multiStep bool
+var outlineWorkers int
+var outlinePath string
+var assumeYes bool
+var interactive bool
+var contextDir string
+var scaffoldContextTokens int
+var skipValidation bool
+var scaffoldFix bool
+var verify bool
+var skipGit bool
+var skipGitignore bool
+var installDeps bool
+var verifyRounds int
+var pinDeps bool
+var scaffoldRegistries map[string]string
+var manifestOut string
+var formatFiles bool
+var scaffoldSecrets string
+
+// scaffoldPrompt guarda o prompt enviado na geração em etapa única, usado para iniciar a conversa de refinamento
+var scaffoldPrompt string
+
+// scaffoldOutlineHash guarda a soma da árvore aprovada no modo multi-etapas, registrada no lock
+var scaffoldOutlineHash string
 
 // scaffoldCmd define o comando "scaffold".
 var scaffoldCmd = &cobra.Command{
@@ -21,68 +54,478 @@ var scaffoldCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		startTime := time.Now()
 
-		fmt.Printf("\n🚀 Iniciando geração do projeto\n")
+		pack, fw, err := resolveLanguagePack(language, framework)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := checkSecretAction(scaffoldSecrets); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		ai.SetSecretAction(scaffoldSecrets)
+		if writeEarly {
+			// Com abort nenhum arquivo pode ser gravado antes da verificação do projeto completo
+			action, err := ai.CurrentSecretAction()
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			if action == ai.SecretAbort {
+				fmt.Print(i18n.T("scaffold.error.write_early_abort"))
+				os.Exit(1)
+			}
+		}
+
+		fmt.Print(i18n.T("scaffold.start"))
 		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-		fmt.Printf("📦 Projeto: %s\n", projectName)
-		fmt.Printf("🔧 Linguagem: %s\n", language)
-		fmt.Printf("📝 Descrição: %s\n", description)
+		fmt.Print(i18n.T("scaffold.project", projectName))
+		fmt.Print(i18n.T("scaffold.language", language))
+		if fw != nil {
+			fmt.Print(i18n.T("scaffold.framework", fw.DisplayName))
+		}
+		fmt.Print(i18n.T("scaffold.description", description))
 		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 
 		// Lista plugins ativos
 		pluginsList := plugins.ListPlugins()
 		if len(pluginsList) > 0 {
-			fmt.Printf("🔌 Plugins ativos: %v\n\n", pluginsList)
+			fmt.Print(i18n.T("scaffold.plugins", pluginsList))
 		}
 
-		fmt.Print("🤖 Gerando estrutura com IA...")
-		response, err := ai.GenerateProjectScaffolding(language, projectName, description, pluginsList)
+		var response string
+		if multiStep {
+			response, err = generateInSteps()
+		} else {
+			response, err = generateInOneStep(pluginsList)
+		}
 		if err != nil {
-			fmt.Printf("\n❌ Erro na geração da estrutura:\n%v\n", err)
+			fmt.Print(i18n.T("scaffold.error.generation", err))
 			if response != "" {
-				fmt.Printf("\nResposta da API:\n%s\n", response)
+				fmt.Print(i18n.T("scaffold.api_response", response))
 			}
 			os.Exit(1)
 		}
 		fmt.Println(" ✅")
 
-		fmt.Print("📂 Criando estrutura do projeto...")
-		err = ai.ExtractAndCreateProject(projectName, response)
+		fmt.Print(i18n.T("scaffold.creating"))
+		manifest, err := ai.ManifestFromResponse(filepath.Base(projectName), response)
+		if err == nil {
+			err = ai.CreateProjectFromManifest(projectName, manifest)
+		}
+		if errors.Is(err, ai.ErrSecretsFound) {
+			// A resposta bruta também não é gravada, pois contém os mesmos segredos
+			fmt.Print(i18n.T("scaffold.error.generation", err))
+			os.Exit(1)
+		}
 		if err != nil {
-			fmt.Printf("\n⚠️  Erro ao criar estrutura padrão, tentando método alternativo...\n")
+			manifest = nil
+			fmt.Print(i18n.T("scaffold.fallback"))
 			err = ai.SaveRawResponse(projectName, response)
 			if err != nil {
-				fmt.Printf("\n❌ Erro ao salvar resposta:\n%v\n", err)
+				fmt.Print(i18n.T("scaffold.error.save_raw", err))
 				os.Exit(1)
 			}
-			fmt.Println("💡 Resposta salva em README.md no diretório do projeto.")
+			fmt.Print(i18n.T("scaffold.raw_saved"))
 		}
 		fmt.Println(" ✅")
+		if err == nil && pack != nil {
+			warnMissingExpectedFiles(pack, fw)
+		}
+
+		// Salva a conversa para que o projeto possa ser refinado depois com zion refine
+		var conv *ai.Conversation
+		if err == nil {
+			conv = startConversation()
+		}
+
+		// Fixa as dependências em versões publicadas antes de instalá-las
+		if err == nil && pinDeps {
+			if report := runPinning(projectName, scaffoldRegistries, false); report != nil && len(report.Manifests) > 0 && conv != nil {
+				recordChanges(conv, "Fixe as dependências dos manifestos nas versões publicadas mais recentes compatíveis.")
+			}
+		}
+
+		// Etapas pós-criação declaradas no pacote da linguagem
+		var steps []postCreateStep
+		installed := make(map[string]bool)
+		if err == nil && pack != nil {
+			if !skipGitignore {
+				steps = append(steps, writeGitignore(projectName, pack.GitignoreFor(fw)))
+			}
+			if installDeps {
+				installSteps := runInstall(projectName, pack.InstallFor(fw))
+				steps = append(steps, installSteps...)
+				installed = installedCommands(installSteps)
+			}
+		}
+
+		// Valida o projeto gerado e, com --fix, faz uma rodada de correção com o modelo
+		if err == nil && !skipValidation {
+			if _, fixed := runValidation(projectName, pack, scaffoldFix, scaffoldContextTokens); fixed != "" && conv != nil {
+				recordFixes(conv, fixed)
+			}
+		}
+		var verifyStatus string
+		if err == nil && verify {
+			status, fixed := runVerification(projectName, pack, fw, verifyRounds, scaffoldContextTokens)
+			if fixed != "" && conv != nil {
+				recordFixes(conv, fixed)
+			}
+			switch status {
+			case verifyPassed:
+				verifyStatus = i18n.T("scaffold.verify_passed")
+			case verifyFailed:
+				verifyStatus = i18n.T("scaffold.verify_failed")
+			}
+		}
+		// Formata os arquivos antes do refinamento, para que a conversa parta deles formatados
+		if err == nil && formatFiles {
+			if report := runFormatting(projectName, false, true); report != nil && len(report.Changed()) > 0 && conv != nil {
+				recordChanges(conv, "Formate os arquivos do projeto.")
+			}
+		}
+		// A base do zion update é salva depois da última etapa pós-criação que altera arquivos;
+		// o refinamento, a seguir, conta como alteração local
+		if err == nil && manifest != nil {
+			saveBaseManifest(manifest, pack, fw)
+		}
+		if interactive && conv != nil {
+			runRefinementLoop(projectName, conv)
+		}
 
 		// Executa plugins
 		if len(pluginsList) > 0 {
-			fmt.Print("🔌 Executando plugins...")
+			fmt.Print(i18n.T("scaffold.running_plugins"))
 			plugins.ExecutePlugins()
 			fmt.Println(" ✅")
 		}
 
+		// Grava o manifesto do projeto final, que pode ser recriado com zion apply
+		if err == nil && manifestOut != "" {
+			if manifest, merr := ai.ReadManifest(projectName); merr != nil {
+				fmt.Print(i18n.T("export.error", merr))
+			} else {
+				manifest.Project.Language = language
+				manifest.Project.Framework = framework
+				manifest.Project.Description = description
+				if merr := ai.WriteManifestFile(manifestOut, "", manifest); merr != nil {
+					fmt.Print(i18n.T("export.error", merr))
+				} else {
+					fmt.Print(i18n.T("scaffold.manifest_saved", manifestOut))
+				}
+			}
+		}
+
+		// Registra a procedência do projeto em .zion/lock.json, incluída no commit inicial
+		if err == nil {
+			writeScaffoldLock(pluginsList)
+		}
+
+		// O commit inicial é feito por último, para incluir correções, refinamentos e alterações dos plugins
+		if err == nil && !skipGit {
+			steps = append(steps, initGitRepository(projectName))
+		}
+
 		elapsedTime := time.Since(startTime)
 
-		fmt.Printf("\n✨ Projeto criado com sucesso! ✨\n")
+		fmt.Print(i18n.T("scaffold.done"))
 		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-		fmt.Printf("📁 Local: %s\n", projectName)
-		fmt.Printf("⏱️  Tempo total: %.2f segundos\n", elapsedTime.Seconds())
+		fmt.Print(i18n.T("scaffold.location", projectName))
+		fmt.Print(i18n.T("scaffold.elapsed", elapsedTime.Seconds()))
+		if verifyStatus != "" {
+			fmt.Print(verifyStatus)
+		}
+		printPostCreateSteps(steps)
+		printUsageSummary()
 		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-		fmt.Printf("💡 Para começar a desenvolver:\n")
+		fmt.Print(i18n.T("scaffold.next_steps"))
 		fmt.Printf("   cd %s\n", projectName)
-		fmt.Printf("   Consulte o README.md para instruções detalhadas\n\n")
+		if pack != nil {
+			for _, command := range pack.PostCreateFor(fw) {
+				if !installed[command] {
+					fmt.Printf("   %s\n", command)
+				}
+			}
+		}
+		fmt.Print(i18n.T("scaffold.readme_hint"))
 	},
 }
 
+// checkSecretAction confere o valor da flag --secrets antes da geração
+func checkSecretAction(action string) error {
+	if action == "" {
+		return nil
+	}
+	for _, known := range ai.SecretActions {
+		if strings.EqualFold(action, known) {
+			return nil
+		}
+	}
+	return fmt.Errorf("valor inválido para --secrets: %s (%s)", action, strings.Join(ai.SecretActions, ", "))
+}
+
+// saveBaseManifest guarda o conteúdo gerado pelo modelo, com as etapas pós-criação que
+// alteram arquivos (pin, .gitignore e formatação), como base do merge de três vias do zion
+// update. As correções de --fix e --verify ficam de fora e contam como alterações locais.
+func saveBaseManifest(manifest *ai.ProjectManifest, pack *languages.Pack, fw *languages.Framework) {
+	base, err := postCreateManifest(manifest, scaffoldSteps(), pack, fw, scaffoldRegistries)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	base.Project.Language = language
+	base.Project.Framework = framework
+	base.Project.Description = description
+	if err := ai.SaveBaseManifest(projectName, base); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// writeScaffoldLock grava o .zion/lock.json com as entradas do scaffold, o prompt efetivo,
+// o modelo, os plugins e a soma de cada arquivo do projeto
+func writeScaffoldLock(pluginsList []string) {
+	inputs := ai.LockInputs{
+		Name:        projectName,
+		Language:    language,
+		Framework:   framework,
+		Description: description,
+		MultiStep:   multiStep,
+		Context:     contextDir,
+		Steps:       scaffoldSteps(),
+		Registries:  scaffoldRegistries,
+	}
+	prompt := ai.LockPrompt{OutlineSHA256: scaffoldOutlineHash}
+	if scaffoldPrompt != "" {
+		prompt.SHA256 = ai.HashText(scaffoldPrompt)
+	}
+	versions := plugins.PluginVersions()
+	used := make(map[string]string, len(pluginsList))
+	for _, name := range pluginsList {
+		used[name] = versions[name]
+	}
+
+	lock := ai.NewScaffoldLock(zionVersion(), inputs, prompt, used)
+	if err := lock.RecordFiles(projectName); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	if err := lock.Save(projectName); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// scaffoldSteps lista as etapas opcionais que alteraram os arquivos gerados
+func scaffoldSteps() []string {
+	var steps []string
+	for _, step := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"pin", pinDeps},
+		{"gitignore", !skipGitignore},
+		{"install", installDeps},
+		{"validate", !skipValidation},
+		{"fix", !skipValidation && scaffoldFix},
+		{"verify", verify},
+		{"format", formatFiles},
+		{"refine", interactive},
+	} {
+		if step.enabled {
+			steps = append(steps, step.name)
+		}
+	}
+	return steps
+}
+
+// generateInOneStep gera toda a estrutura em uma única requisição, opcionalmente em streaming
+func generateInOneStep(pluginsList []string) (string, error) {
+	fmt.Print(i18n.T("scaffold.generating"))
+	opts := ai.ScaffoldOptions{Stream: stream, Framework: framework}
+	if contextDir != "" {
+		projectContext, err := ai.CollectProjectContext(contextDir, scaffoldContextTokens)
+		if err != nil {
+			return "", err
+		}
+		fmt.Print(i18n.T("scaffold.context", contextDir, len(projectContext.Tree), len(projectContext.Files), projectContext.Tokens))
+		opts.Context = projectContext
+	}
+	opts.OnPrompt = func(prompt string) {
+		scaffoldPrompt = prompt
+	}
+	if stream {
+		fmt.Println()
+		opts.OnFile = func(file ai.StreamedFile) {
+			fmt.Printf("\r   ├── %s\n", file.Path)
+			if writeEarly {
+				if err := ai.WriteStreamedFile(projectName, file); err != nil {
+					fmt.Printf("   ⚠️  %v\n", err)
+				}
+			}
+		}
+		opts.OnProgress = func(progress ai.StreamProgress) {
+			fmt.Print(i18n.T("scaffold.stream_progress", progress.Files, progress.Tokens))
+		}
+	}
+	response, err := ai.GenerateProjectScaffoldingWithOptions(language, projectName, description, pluginsList, opts)
+	if stream {
+		fmt.Print(i18n.T("scaffold.generation_done"))
+	}
+	return response, err
+}
+
+// generateInSteps gera primeiro a árvore do projeto, deixa o usuário revisá-la e
+// depois gera o conteúdo de cada arquivo em requisições separadas
+func generateInSteps() (string, error) {
+	var outline *ai.ProjectOutline
+	var err error
+	if outlinePath != "" {
+		fmt.Print(i18n.T("scaffold.outline.using", outlinePath))
+		outline, err = ai.LoadProjectOutline(outlinePath)
+	} else {
+		fmt.Print(i18n.T("scaffold.outline.generating"))
+		outline, err = ai.GenerateProjectOutline(language, framework, projectName, description)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Print(i18n.T("scaffold.outline.planned", len(outline.Directories), len(outline.Files)))
+	fmt.Print(ai.FormatOutline(outline))
+
+	if !assumeYes {
+		savedPath := projectName + ".outline.json"
+		if err := ai.SaveProjectOutline(savedPath, outline); err == nil {
+			fmt.Print(i18n.T("scaffold.outline.saved", savedPath, savedPath))
+		}
+		if !askConfirmation(i18n.T("scaffold.outline.confirm")) {
+			fmt.Print(i18n.T("scaffold.cancelled"))
+			os.Exit(0)
+		}
+	}
+
+	scaffoldOutlineHash = ai.OutlineHash(outline)
+
+	fmt.Print(i18n.T("scaffold.outline.files", len(outline.Files), outlineWorkers))
+	done := 0
+	return ai.GenerateFilesFromOutline(language, framework, projectName, description, outline, outlineWorkers, func(path string, err error) {
+		done++
+		if err != nil {
+			fmt.Printf("   ❌ [%d/%d] %s: %v\n", done, len(outline.Files), path, err)
+			return
+		}
+		fmt.Printf("   ├── [%d/%d] %s\n", done, len(outline.Files), path)
+	})
+}
+
+// resolveLanguagePack localiza o pacote da linguagem e o framework escolhido.
+// Linguagens sem pacote continuam aceitas, mas sem suporte a frameworks.
+func resolveLanguagePack(language, framework string) (*languages.Pack, *languages.Framework, error) {
+	registry, err := languages.Load(".")
+	if err != nil {
+		return nil, nil, err
+	}
+	pack := registry.Find(language)
+	if framework == "" {
+		return pack, nil, nil
+	}
+	if pack == nil {
+		return nil, nil, fmt.Errorf("linguagem '%s' não possui pacote de linguagem; use 'zion languages' para ver as disponíveis", language)
+	}
+	fw, err := pack.Framework(framework)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pack, fw, nil
+}
+
+// warnMissingExpectedFiles avisa quando o projeto gerado não contém os arquivos esperados pelo pacote
+func warnMissingExpectedFiles(pack *languages.Pack, fw *languages.Framework) {
+	var missing []string
+	for _, file := range pack.ExpectedFilesFor(fw) {
+		if _, err := os.Stat(filepath.Join(projectName, file)); os.IsNotExist(err) {
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Print(i18n.T("scaffold.missing_files", pack.DisplayName, strings.Join(missing, ", ")))
+	}
+}
+
+// startConversation inicia e salva a conversa de refinamento do projeto recém-criado. A
+// conversa parte dos arquivos gravados, e não da resposta do modelo, para que os segredos
+// trocados pela verificação não fiquem em .zion/conversation.json.
+func startConversation() *ai.Conversation {
+	var conv *ai.Conversation
+	if scaffoldPrompt != "" {
+		manifest, err := ai.ReadProjectManifest(projectName)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			return nil
+		}
+		conv = ai.NewConversation(scaffoldPrompt, manifest)
+	} else {
+		// No modo multi-etapas não há um prompt único: a conversa parte dos arquivos gerados
+		var err error
+		conv, err = ai.ConversationFromProject(projectName)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			return nil
+		}
+	}
+	if err := conv.Save(projectName); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return conv
+}
+
+// recordFixes registra na conversa de refinamento as correções feitas após a validação
+func recordFixes(conv *ai.Conversation, problems string) {
+	recordChanges(conv, "Corrija os problemas encontrados na validação do projeto:\n"+problems)
+}
+
+// recordChanges registra na conversa de refinamento o estado atual do projeto como
+// resultado da instrução, para que os próximos refinamentos partam dele
+func recordChanges(conv *ai.Conversation, instruction string) {
+	manifest, err := ai.ReadProjectManifest(projectName)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	if err := conv.Accept(instruction, manifest); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	} else if err := conv.Save(projectName); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
 func init() {
 	// Configura flags para o comando scaffold
 	scaffoldCmd.Flags().StringVarP(&language, "language", "l", "", "Linguagem para o scaffold (ex: go, python, etc)")
+	scaffoldCmd.Flags().StringVarP(&framework, "framework", "f", "", "Framework do pacote de linguagem (ex: fastapi, gin, nestjs); veja 'zion languages'")
 	scaffoldCmd.Flags().StringVarP(&projectName, "name", "n", "", "Nome do projeto")
 	scaffoldCmd.Flags().StringVarP(&description, "description", "d", "", "Descrição objetiva da estrutura desejada")
+	scaffoldCmd.Flags().BoolVar(&stream, "stream", true, "Recebe a resposta em streaming, anunciando cada arquivo assim que ele fica pronto")
+	scaffoldCmd.Flags().BoolVar(&writeEarly, "write-early", false, "Grava cada arquivo no disco assim que ele é recebido durante o streaming")
+	scaffoldCmd.Flags().BoolVar(&multiStep, "multi-step", false, "Gera primeiro a árvore do projeto e depois cada arquivo em requisições separadas")
+	scaffoldCmd.Flags().IntVar(&outlineWorkers, "workers", ai.DefaultOutlineWorkers, "Número máximo de requisições simultâneas no modo multi-etapas")
+	scaffoldCmd.Flags().StringVar(&outlinePath, "outline", "", "Arquivo JSON com a árvore do projeto a usar no modo multi-etapas")
+	scaffoldCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Não pede confirmação antes de gerar os arquivos")
+	scaffoldCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Após gerar o projeto, permite refiná-lo com novas instruções em uma conversa com a IA")
+	scaffoldCmd.Flags().StringVar(&contextDir, "context", "", "Diretório de um projeto existente a usar como referência na geração")
+	scaffoldCmd.Flags().IntVar(&scaffoldContextTokens, "context-tokens", ai.DefaultContextTokens, "Orçamento aproximado de tokens para o conteúdo do projeto de referência")
+	scaffoldCmd.Flags().BoolVar(&skipValidation, "no-validate", false, "Não valida os arquivos do projeto gerado")
+	scaffoldCmd.Flags().BoolVar(&scaffoldFix, "fix", false, "Envia os problemas encontrados na validação ao modelo para uma rodada de correção")
+	scaffoldCmd.Flags().BoolVar(&verify, "verify", false, "Executa os comandos de verificação do pacote (build, checagem de tipos) e pede ao modelo correções para as falhas")
+	scaffoldCmd.Flags().IntVar(&verifyRounds, "verify-rounds", defaultVerifyRounds, "Número máximo de rodadas de correção no modo --verify")
+	scaffoldCmd.Flags().BoolVar(&pinDeps, "pin", false, "Fixa as dependências dos manifestos na versão publicada mais recente compatível, consultando os registros")
+	scaffoldCmd.Flags().StringToStringVar(&scaffoldRegistries, "registry", nil, "Registro de um ecossistema para --pin, como npm=http://localhost:4873 (pode ser repetida)")
+	scaffoldCmd.Flags().BoolVar(&installDeps, "install", false, "Executa os comandos de instalação de dependências do pacote, que rodam scripts do projeto gerado")
+	scaffoldCmd.Flags().BoolVar(&formatFiles, "format", false, "Formata os arquivos gerados (go/format, JSON, YAML, .editorconfig e os formatadores externos configurados)")
+	scaffoldCmd.Flags().StringVar(&scaffoldSecrets, "secrets", "", "O que fazer com possíveis segredos nos arquivos gerados: warn, redact ou abort (padrão: secrets.action do config.yaml, ou warn)")
+	scaffoldCmd.Flags().StringVar(&manifestOut, "manifest-out", "", "Grava o manifesto do projeto gerado neste arquivo (.json, .yaml, .yml ou .toml)")
+	scaffoldCmd.Flags().BoolVar(&skipGitignore, "no-gitignore", false, "Não cria nem completa o .gitignore com os padrões da linguagem")
+	scaffoldCmd.Flags().BoolVar(&skipGit, "no-git", false, "Não cria o repositório git nem o commit inicial")
 	scaffoldCmd.MarkFlagRequired("language")
 	scaffoldCmd.MarkFlagRequired("name")
 
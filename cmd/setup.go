@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"zion/config"
+	"zion/i18n"
 )
 
 var setupCmd = &cobra.Command{
@@ -18,60 +19,27 @@ var setupCmd = &cobra.Command{
 		// Carregar a configuração
 		cfg := config.LoadConfig()
 
-		fmt.Printf("Configurando o ambiente Zion em: %s\n", cfg.HomeDir)
+		fmt.Print(i18n.T("setup.start", cfg.HomeDir))
 
 		// Criar o diretório de plugins se não existir
 		if err := os.MkdirAll(cfg.PluginsDir, 0755); err != nil {
-			fmt.Printf("Erro ao criar diretório de plugins: %v\n", err)
+			fmt.Print(i18n.T("setup.error.plugins_dir", err))
 			return
 		}
 
 		// Criar arquivo README.md no diretório home
 		readmePath := filepath.Join(cfg.HomeDir, "README.md")
-		readmeContent := `# Zion Home Directory
-
-Este é o diretório home do Zion, onde são armazenadas configurações e plugins.
-
-## Estrutura
-
-- plugins/ - Diretório onde os plugins são armazenados (apenas para sistemas Unix)
-- config.yaml - Arquivo de configuração (opcional)
-
-## Plugins no Windows
-
-No Windows, o Go não suporta plugins dinâmicos (buildmode=plugin). Portanto, os plugins no Windows são implementados estaticamente no código fonte do Zion.
-
-Para adicionar um novo plugin no Windows:
-
-1. Crie um novo arquivo .go no diretório plugins/ do código fonte do Zion
-2. Implemente a interface Plugin
-3. Registre o plugin usando RegisterPlugin() na função init()
-4. Recompile o Zion
-
-## Plugins em sistemas Unix (Linux/macOS)
-
-Em sistemas Unix, os plugins podem ser compilados como arquivos .so e colocados no diretório plugins/.
-Exemplo de como compilar um plugin:
-
-` + "```bash" + `
-go build -buildmode=plugin -o hello_world.so hello_world.go
-` + "```" + `
-
-## Plugin HelloWorld
-
-O plugin HelloWorld já está incluído estaticamente no Zion e demonstra como criar plugins.
-Ele adiciona instruções extras ao prompt de geração de scaffold e exibe mensagens durante o processo.
-`
+		readmeContent := i18n.Doc("setup_home_readme.md", i18n.Language())
 
 		if err := os.WriteFile(readmePath, []byte(readmeContent), 0644); err != nil {
-			fmt.Printf("Erro ao criar arquivo README.md: %v\n", err)
+			fmt.Print(i18n.T("setup.error.readme", err))
 			return
 		}
 
 		// Criar diretório para o plugin HelloWorld de exemplo
 		helloWorldDir := filepath.Join(cfg.HomeDir, "plugins", "hello_world")
 		if err := os.MkdirAll(helloWorldDir, 0755); err != nil {
-			fmt.Printf("Erro ao criar diretório do plugin HelloWorld: %v\n", err)
+			fmt.Print(i18n.T("setup.error.hello_dir", err))
 			return
 		}
 
@@ -170,59 +138,28 @@ var Plugin HelloWorldPlugin
 `
 
 		if err := os.WriteFile(helloWorldPath, []byte(helloWorldContent), 0644); err != nil {
-			fmt.Printf("Erro ao criar arquivo hello_world.go: %v\n", err)
+			fmt.Print(i18n.T("setup.error.hello_file", err))
 			return
 		}
 
 		// Criar arquivo README.md para o plugin HelloWorld
 		helloWorldReadmePath := filepath.Join(helloWorldDir, "README.md")
-		helloWorldReadmeContent := `# Plugin HelloWorld
-
-Este é um plugin de exemplo para o Zion que demonstra como criar plugins que podem modificar o processo de geração de scaffold.
-
-## Funcionalidades
-
-- Adiciona uma mensagem de boas-vindas ao gerar um projeto
-- Modifica o prompt para incluir requisitos adicionais
-- Demonstra como usar os hooks BeforeGeneration, ModifyPrompt e AfterGeneration
-
-## Compilação
-
-Para compilar o plugin, execute:
-
-` + "```bash" + `
-go build -buildmode=plugin -o hello_world.so hello_world.go
-` + "```" + `
-
-## Instalação
-
-Copie o arquivo hello_world.so para o diretório ~/.zion/plugins/:
-
-` + "```bash" + `
-cp hello_world.so ~/.zion/plugins/
-` + "```" + `
-
-## Uso
-
-Após a instalação, o plugin será carregado automaticamente quando você executar o comando zion scaffold.
-`
+		helloWorldReadmeContent := i18n.Doc("setup_plugin_readme.md", i18n.Language())
 
 		if err := os.WriteFile(helloWorldReadmePath, []byte(helloWorldReadmeContent), 0644); err != nil {
-			fmt.Printf("Erro ao criar arquivo README.md do plugin HelloWorld: %v\n", err)
+			fmt.Print(i18n.T("setup.error.hello_readme", err))
 			return
 		}
 
-		fmt.Println("Configuração concluída com sucesso!")
-		fmt.Println("Diretório home do Zion:", cfg.HomeDir)
-		fmt.Println("Diretório de plugins:", cfg.PluginsDir)
-		fmt.Println("Plugin HelloWorld de exemplo criado em:", helloWorldDir)
+		fmt.Print(i18n.T("setup.done"))
+		fmt.Print(i18n.T("setup.home", cfg.HomeDir))
+		fmt.Print(i18n.T("setup.plugins_dir", cfg.PluginsDir))
+		fmt.Print(i18n.T("setup.hello_created", helloWorldDir))
 		
 		if runtime.GOOS == "windows" {
-			fmt.Println("\nNota: No Windows, os plugins são implementados estaticamente.")
-			fmt.Println("O plugin HelloWorld já está incluído no código fonte do Zion.")
-			fmt.Println("Para criar novos plugins, adicione-os ao diretório plugins/ do código fonte e recompile o Zion.")
+			fmt.Print(i18n.T("setup.windows_note"))
 		} else {
-			fmt.Println("\nPara compilar e instalar o plugin HelloWorld, execute:")
+			fmt.Print(i18n.T("setup.unix_build"))
 			fmt.Printf("cd %s\n", helloWorldDir)
 			fmt.Println("go build -buildmode=plugin -o hello_world.so hello_world.go")
 			fmt.Printf("cp hello_world.so %s\n", cfg.PluginsDir)
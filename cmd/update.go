@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"zion/ai"
+	"zion/i18n"
+	"zion/plugins"
+
+	"github.com/spf13/cobra"
+)
+
+var updateDryRun bool
+var updateYes bool
+var updateContextTokens int
+var updateWorkers int
+
+// updateCmd define o comando "update".
+var updateCmd = &cobra.Command{
+	Use:   "update [diretório]",
+	Short: "Atualiza um projeto gerado com uma nova geração, preservando as alterações locais",
+	Long: `Gera o projeto de novo com as entradas registradas em .zion/lock.json, usando os prompts
+e pacotes de linguagem atuais, e faz o merge de três vias entre o conteúdo gerado
+originalmente (.zion/base.json), os arquivos atuais e a nova geração. Arquivos alterados
+só na nova geração são atualizados, alterações locais são mantidas e trechos alterados
+dos dois lados ficam entre marcadores de conflito (<<<<<<< local / >>>>>>> zion).
+
+Respostas do modelo ficam em cache; use --no-cache para pedir uma geração nova mesmo
+quando o prompt não mudou.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		startTime := time.Now()
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		lock, err := ai.LoadScaffoldLock(dir)
+		if err != nil {
+			fmt.Print(i18n.T("update.error", err))
+			os.Exit(1)
+		}
+		base, err := ai.LoadBaseManifest(dir)
+		if err != nil {
+			fmt.Print(i18n.T("update.error", err))
+			os.Exit(1)
+		}
+
+		fmt.Print(i18n.T("update.start", dir, lock.Inputs.Language, lock.ZionVersion))
+		var promptHash, outlineHash string
+		next, err := ai.RegenerateScaffold(lock, ai.RegenerateOptions{
+			Plugins:       plugins.ListPlugins(),
+			ContextTokens: updateContextTokens,
+			Workers:       updateWorkers,
+			OnPrompt: func(prompt string) {
+				promptHash = ai.HashText(prompt)
+			},
+			OnOutline: func(outline *ai.ProjectOutline) {
+				outlineHash = ai.OutlineHash(outline)
+			},
+		})
+		if err != nil {
+			fmt.Print(i18n.T("update.error", err))
+			os.Exit(1)
+		}
+		if promptHash != "" && promptHash == lock.Prompt.SHA256 {
+			fmt.Print(i18n.T("update.same_prompt"))
+		}
+
+		// A base inclui as etapas pós-criação que alteram arquivos; a nova geração passa por elas também
+		if next, err = postCreateFromLock(lock, next); err != nil {
+			fmt.Print(i18n.T("update.error", err))
+			os.Exit(1)
+		}
+
+		merged, err := ai.MergeProject(dir, base, next)
+		if err != nil {
+			fmt.Print(i18n.T("update.error", err))
+			os.Exit(1)
+		}
+		conflicts := printMergedFiles(merged)
+		if updateDryRun {
+			fmt.Print(i18n.T("update.dry_run"))
+			return
+		}
+
+		changes := ai.MergeChanges(merged)
+		if len(changes) > 0 {
+			if !updateYes {
+				for _, change := range changes {
+					fmt.Printf("\n%s", change.Diff())
+				}
+				if !askConfirmation(i18n.T("update.confirm")) {
+					fmt.Print(i18n.T("refine.discarded"))
+					return
+				}
+			}
+			if err := ai.ApplyFileChanges(dir, changes); err != nil {
+				fmt.Print(i18n.T("update.error", err))
+				os.Exit(1)
+			}
+		}
+
+		// A nova geração passa a ser a base das próximas atualizações
+		if err := ai.SaveBaseManifest(dir, next); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		lock.ZionVersion = zionVersion()
+		lock.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		lock.Prompt = ai.LockPrompt{SHA256: promptHash, OutlineSHA256: outlineHash}
+		lock.Plugins = plugins.PluginVersions()
+		if err := lock.RecordFiles(dir); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		} else if err := lock.Save(dir); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+
+		fmt.Print(i18n.T("update.done", len(changes), conflicts, time.Since(startTime).Seconds()))
+		if conflicts > 0 {
+			fmt.Print(i18n.T("update.conflicts_hint"))
+		}
+		printUsageSummary()
+	},
+}
+
+// mergeNote traduz a situação de um conflito sem marcadores retornada por ai.MergeProject
+func mergeNote(note string) string {
+	switch note {
+	case ai.NoteRemovedLocally:
+		return i18n.T("update.note.removed_locally")
+	case ai.NoteRemovedUpstream:
+		return i18n.T("update.note.removed_upstream")
+	case ai.NoteBinary:
+		return i18n.T("update.note.binary")
+	}
+	return note
+}
+
+// printMergedFiles lista o resultado do merge de cada arquivo e retorna o número de conflitos
+func printMergedFiles(merged []ai.MergedFile) int {
+	if len(merged) == 0 {
+		fmt.Print(i18n.T("update.up_to_date"))
+		return 0
+	}
+	conflicts := 0
+	fmt.Print(i18n.T("update.changed", len(merged)))
+	for _, file := range merged {
+		switch {
+		case file.Conflict && file.Note != "":
+			fmt.Printf("   ⚠️  %s: %s\n", file.Path, mergeNote(file.Note))
+		case file.Conflict:
+			fmt.Printf("   ⚠️  %s %s%s", changeSymbol(file.Kind), file.Path, i18n.T("update.conflict"))
+		default:
+			fmt.Printf("   %s %s\n", changeSymbol(file.Kind), file.Path)
+		}
+		if file.Conflict {
+			conflicts++
+		}
+	}
+	return conflicts
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Mostra o resultado do merge sem gravar os arquivos")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Aplica o merge sem pedir confirmação")
+	updateCmd.Flags().IntVar(&updateContextTokens, "context-tokens", ai.DefaultContextTokens, "Orçamento aproximado de tokens do projeto de referência registrado no lock")
+	updateCmd.Flags().IntVar(&updateWorkers, "workers", ai.DefaultOutlineWorkers, "Número máximo de requisições simultâneas para projetos gerados em multi-etapas")
+
+	rootCmd.AddCommand(updateCmd)
+}
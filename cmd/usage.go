@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"zion/ai"
+	"zion/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var usageDays int
+var usageProfile string
+
+// usageCmd define o comando "usage".
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Mostra o consumo de tokens e o custo estimado por dia, perfil e modelo",
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := ai.LoadUsageLog()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		// Filtra pelo período e perfil pedidos
+		var since time.Time
+		if usageDays > 0 {
+			since = time.Now().AddDate(0, 0, -usageDays)
+		}
+		filtered := records[:0]
+		for _, record := range records {
+			if !since.IsZero() && record.Time.Before(since) {
+				continue
+			}
+			if usageProfile != "" && record.Profile != usageProfile {
+				continue
+			}
+			filtered = append(filtered, record)
+		}
+
+		if len(filtered) == 0 {
+			fmt.Print(i18n.T("usage.empty"))
+			return
+		}
+
+		var total ai.UsageSummary
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("usage.header"))
+		for _, group := range ai.AggregateUsage(filtered) {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%d\t%.4f\n", group.Day, group.Profile, group.Model,
+				group.Calls, group.PromptTokens, group.ResponseTokens, group.TotalTokens, group.Cost)
+			total.Calls += group.Calls
+			total.PromptTokens += group.PromptTokens
+			total.ResponseTokens += group.ResponseTokens
+			total.TotalTokens += group.TotalTokens
+			total.Cost += group.Cost
+		}
+		fmt.Fprintf(w, "%s\t\t\t%d\t%d\t%d\t%d\t%.4f\n", "TOTAL",
+			total.Calls, total.PromptTokens, total.ResponseTokens, total.TotalTokens, total.Cost)
+		w.Flush()
+	},
+}
+
+// printUsageSummary exibe o consumo de tokens e o custo estimado da execução atual
+func printUsageSummary() {
+	usage := ai.SessionUsage()
+	if usage.Calls == 0 {
+		return
+	}
+	fmt.Print(i18n.T("usage.tokens", usage.PromptTokens, usage.ResponseTokens, usage.TotalTokens, usage.Calls))
+	fmt.Print(i18n.T("usage.cost", usage.Cost, strings.Join(usage.Models, ", ")))
+}
+
+func init() {
+	usageCmd.Flags().IntVar(&usageDays, "days", 0, "Considera apenas os últimos N dias")
+	usageCmd.Flags().StringVar(&usageProfile, "profile", "", "Considera apenas o perfil informado")
+
+	rootCmd.AddCommand(usageCmd)
+}
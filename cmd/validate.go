@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"zion/ai"
+	"zion/i18n"
+	"zion/languages"
+
+	"github.com/spf13/cobra"
+)
+
+var validateLanguage string
+var validateFix bool
+var validateContextTokens int
+var validateVerify bool
+var validateVerifyRounds int
+
+// validateCmd define o comando "validate".
+var validateCmd = &cobra.Command{
+	Use:   "validate [diretório]",
+	Short: "Verifica offline se os arquivos de um projeto são válidos",
+	Long: `Analisa os arquivos JSON, YAML e TOML do projeto e executa os validadores do pacote
+da linguagem: package.json, go.mod e arquivos Go, pontos de entrada declarados e imports
+locais que não correspondem a nenhum arquivo. Com --fix, os problemas encontrados são
+enviados ao modelo para uma rodada de correção. Com --verify, os comandos de verificação
+do pacote (como go build ou tsc --noEmit) são executados e suas falhas corrigidas pelo
+modelo por até --verify-rounds rodadas.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		registry, err := languages.Load(dir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		var pack *languages.Pack
+		if validateLanguage != "" {
+			if pack = registry.Find(validateLanguage); pack == nil {
+				fmt.Print(i18n.T("validate.unknown_language", validateLanguage))
+				os.Exit(1)
+			}
+		} else if pack = registry.Detect(dir); pack != nil {
+			fmt.Print(i18n.T("validate.detected", pack.DisplayName))
+		}
+
+		report, _ := runValidation(dir, pack, validateFix, validateContextTokens)
+		status := verifySkipped
+		if validateVerify {
+			status, _ = runVerification(dir, pack, nil, validateVerifyRounds, validateContextTokens)
+		}
+		if report == nil || !report.OK() || status == verifyFailed {
+			os.Exit(1)
+		}
+	},
+}
+
+// runValidation valida o projeto em dir com os validadores do pacote e mostra o relatório.
+// Com fix, os problemas são enviados ao modelo para uma rodada de correção e o projeto é
+// validado novamente. Retorna o último relatório e, se houve correção, os problemas corrigidos.
+func runValidation(dir string, pack *languages.Pack, fix bool, tokenBudget int) (*ai.ValidationReport, string) {
+	var validators []string
+	if pack != nil {
+		validators = pack.Validators
+	}
+
+	fmt.Print(i18n.T("validate.start"))
+	report, err := ai.ValidateProject(dir, validators)
+	if err != nil {
+		fmt.Print(i18n.T("validate.error", err))
+		return nil, ""
+	}
+	printValidationReport(report)
+	if report.OK() {
+		return report, ""
+	}
+	if !fix {
+		fmt.Print(i18n.T("validate.fix_hint"))
+		return report, ""
+	}
+
+	problems := report.String()
+	if !applyFixes(dir, problems, report.Paths(), tokenBudget) {
+		return report, ""
+	}
+
+	fmt.Print(i18n.T("validate.start"))
+	if report, err = ai.ValidateProject(dir, validators); err != nil {
+		fmt.Print(i18n.T("validate.error", err))
+		return nil, problems
+	}
+	printValidationReport(report)
+	return report, problems
+}
+
+// applyFixes pede ao modelo correções para os problemas, mostra os arquivos alterados e os
+// grava no projeto. Retorna se alguma correção foi aplicada.
+func applyFixes(dir, problems string, focus []string, tokenBudget int) bool {
+	fmt.Print(i18n.T("validate.fixing"))
+	manifest, err := ai.GenerateFixes(dir, problems, focus, tokenBudget)
+	if err != nil {
+		fmt.Print(i18n.T("validate.fix_error", err))
+		return false
+	}
+	changes, err := ai.FeatureChanges(dir, manifest)
+	if err != nil {
+		fmt.Print(i18n.T("validate.fix_error", err))
+		return false
+	}
+	if len(changes) == 0 {
+		fmt.Print(i18n.T("validate.fix_none"))
+		return false
+	}
+	fmt.Print(i18n.T("validate.fix_applied", len(changes)))
+	for _, change := range changes {
+		fmt.Printf("   %s %s\n", changeSymbol(change.Kind), change.Path)
+	}
+	if err := ai.ApplyFileChanges(dir, changes); err != nil {
+		fmt.Print(i18n.T("validate.fix_error", err))
+		return false
+	}
+	return true
+}
+
+// printValidationReport mostra o resumo da validação e cada problema encontrado
+func printValidationReport(report *ai.ValidationReport) {
+	validators := strings.Join(report.Validators, ", ")
+	if report.OK() {
+		fmt.Print(i18n.T("validate.ok", report.Files, validators))
+		return
+	}
+	fmt.Print(i18n.T("validate.issues", len(report.Issues), report.Files, validators))
+	for _, issue := range report.Issues {
+		fmt.Printf("   • [%s] %s\n", issue.Validator, issue)
+	}
+}
+
+func init() {
+	validateCmd.Flags().StringVarP(&validateLanguage, "language", "l", "", "Linguagem do projeto (padrão: detectada pelos arquivos esperados de cada pacote)")
+	validateCmd.Flags().BoolVar(&validateFix, "fix", false, "Envia os problemas encontrados ao modelo para uma rodada de correção")
+	validateCmd.Flags().IntVar(&validateContextTokens, "context-tokens", ai.DefaultContextTokens, "Orçamento aproximado de tokens para o conteúdo do projeto enviado ao modelo")
+	validateCmd.Flags().BoolVar(&validateVerify, "verify", false, "Executa também os comandos de verificação do pacote e pede ao modelo correções para as falhas")
+	validateCmd.Flags().IntVar(&validateVerifyRounds, "verify-rounds", defaultVerifyRounds, "Número máximo de rodadas de correção no modo --verify")
+
+	rootCmd.AddCommand(validateCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"zion/i18n"
+	"zion/languages"
+)
+
+// defaultVerifyRounds é o número padrão de rodadas de correção no modo --verify
+const defaultVerifyRounds = 3
+
+// verifyTimeout limita o tempo de cada comando de verificação
+const verifyTimeout = 5 * time.Minute
+
+// maxVerifyOutput é o tamanho máximo da saída de um comando enviada ao modelo; o final é mantido
+const maxVerifyOutput = 8 * 1024
+
+// verifyOutputPath encontra caminhos de arquivo com número de linha na saída dos compiladores
+var verifyOutputPath = regexp.MustCompile(`([\w./\\-]+\.\w+)[:(](\d+)`)
+
+// Situação final do modo --verify
+const (
+	verifySkipped = iota
+	verifyPassed
+	verifyFailed
+)
+
+// verifyFailures formata as falhas dos comandos para o prompt de correção
+func verifyFailures(results []commandResult) string {
+	var b strings.Builder
+	for _, result := range results {
+		if result.Skipped || result.Err == nil {
+			continue
+		}
+		output := result.Output
+		if len(output) > maxVerifyOutput {
+			output = "[...]\n" + output[len(output)-maxVerifyOutput:]
+		}
+		fmt.Fprintf(&b, "$ %s\n(%v)\n%s\n\n", result.Command, result.Err, output)
+	}
+	return b.String()
+}
+
+// referencedFiles retorna os arquivos do projeto mencionados na saída dos comandos
+func referencedFiles(dir, output string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, match := range verifyOutputPath.FindAllStringSubmatch(output, -1) {
+		rel := filepath.ToSlash(filepath.Clean(match[1]))
+		if filepath.IsAbs(match[1]) {
+			var err error
+			if rel, err = filepath.Rel(dir, match[1]); err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+		}
+		if seen[rel] || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); err == nil && !info.IsDir() {
+			seen[rel] = true
+			files = append(files, rel)
+		}
+	}
+	return files
+}
+
+// runVerification executa os comandos de verificação do pacote no projeto em dir. Enquanto
+// houver falhas, envia a saída ao modelo e aplica as correções, por até rounds rodadas.
+// Retorna a situação final e os problemas enviados ao modelo nas correções aplicadas.
+func runVerification(dir string, pack *languages.Pack, fw *languages.Framework, rounds int, tokenBudget int) (int, string) {
+	var commands []string
+	if pack != nil {
+		commands = pack.VerifyFor(fw)
+	}
+	if len(commands) == 0 {
+		fmt.Print(i18n.T("verify.no_commands"))
+		return verifySkipped, ""
+	}
+
+	var fixed strings.Builder
+	for round := 0; ; round++ {
+		fmt.Print(i18n.T("verify.start", round+1))
+		var results []commandResult
+		ran := 0
+		for _, command := range commands {
+			result := runCommandLine(dir, command, verifyTimeout, false)
+			results = append(results, result)
+			switch {
+			case result.Skipped:
+				fmt.Print(i18n.T("verify.skipped", command))
+			case result.Err != nil:
+				ran++
+				fmt.Print(i18n.T("verify.failed", command, result.Err))
+				for _, line := range tailLines(result.Output, 10) {
+					fmt.Printf("      │ %s\n", line)
+				}
+			default:
+				ran++
+				fmt.Print(i18n.T("verify.passed", command))
+			}
+		}
+
+		failures := verifyFailures(results)
+		switch {
+		case ran == 0:
+			fmt.Print(i18n.T("verify.none_available"))
+			return verifySkipped, fixed.String()
+		case failures == "":
+			fmt.Print(i18n.T("verify.success", round))
+			return verifyPassed, fixed.String()
+		case round == rounds:
+			fmt.Print(i18n.T("verify.gave_up", round))
+			return verifyFailed, fixed.String()
+		}
+
+		if !applyFixes(dir, failures, referencedFiles(dir, failures), tokenBudget) {
+			return verifyFailed, fixed.String()
+		}
+		fixed.WriteString(failures)
+	}
+}
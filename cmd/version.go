@@ -0,0 +1,23 @@
+package cmd
+
+import "runtime/debug"
+
+// Version é a versão do Zion, definida na compilação com
+// -ldflags "-X zion/cmd.Version=v1.2.3". Sem ela, é usada a versão do módulo
+// instalado com go install.
+var Version = ""
+
+// zionVersion retorna a versão do Zion em execução
+func zionVersion() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func init() {
+	rootCmd.Version = zionVersion()
+}
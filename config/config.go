@@ -1,14 +1,154 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
+// DefaultModel é o modelo Gemini usado quando nenhum outro é configurado
+const DefaultModel = "gemini-2.0-flash"
+
+// DefaultProfile é o nome do perfil usado quando nenhum outro é configurado
+const DefaultProfile = "default"
+
+// DefaultCacheTTL é o tempo de validade padrão das respostas em cache
+const DefaultCacheTTL = 24 * time.Hour
+
+// ModelPrice é o preço em dólares por milhão de tokens de um modelo
+type ModelPrice struct {
+	InputPerMillion  float64 `yaml:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million"`
+}
+
+// DefaultPrices é a tabela de preços usada quando config.yaml não define outra
+var DefaultPrices = map[string]ModelPrice{
+	"gemini-2.0-flash":      {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	"gemini-2.0-flash-lite": {InputPerMillion: 0.075, OutputPerMillion: 0.30},
+	"gemini-2.5-flash":      {InputPerMillion: 0.30, OutputPerMillion: 2.50},
+	"gemini-2.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 10.00},
+	"gemini-1.5-flash":      {InputPerMillion: 0.075, OutputPerMillion: 0.30},
+	"gemini-1.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 5.00},
+}
+
+// DefaultRegistries são os registros de pacotes consultados na fixação de versões das
+// dependências quando config.yaml não aponta para um espelho
+var DefaultRegistries = map[string]string{
+	"npm":   "https://registry.npmjs.org",
+	"go":    "https://proxy.golang.org",
+	"pypi":  "https://pypi.org/simple",
+	"cargo": "https://index.crates.io",
+}
+
+// JSONStyle define como são gravados os arquivos JSON que o modelo devolve como objeto
+type JSONStyle struct {
+	// Indent é o número de espaços de cada nível ("2", "4") ou "tab"
+	Indent string `yaml:"indent"`
+	// FinalNewline termina o arquivo com uma quebra de linha (o padrão, quando omitido)
+	FinalNewline *bool `yaml:"final_newline"`
+}
+
+// EndsWithNewline indica se o arquivo termina com uma quebra de linha
+func (s JSONStyle) EndsWithNewline() bool {
+	return s.FinalNewline == nil || *s.FinalNewline
+}
+
+// IndentString retorna a indentação de um nível do estilo
+func (s JSONStyle) IndentString() string {
+	if s.Indent == "\t" || strings.EqualFold(strings.TrimSpace(s.Indent), "tab") {
+		return "\t"
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(s.Indent)); err == nil && n > 0 && n <= 8 {
+		return strings.Repeat(" ", n)
+	}
+	return "  "
+}
+
+// DefaultJSONStyles são os estilos usados quando config.yaml não define outros, por nome
+// de arquivo ou por extensão
+var DefaultJSONStyles = map[string]JSONStyle{
+	".json":         {Indent: "2"},
+	"composer.json": {Indent: "4"},
+}
+
+// SecretsConfig define a verificação de segredos nos arquivos gerados pelo modelo
+type SecretsConfig struct {
+	// Action é o que fazer com os segredos encontrados: warn, redact ou abort
+	Action string `yaml:"action"`
+	// Allow são expressões regulares de valores aceitos, como chaves de teste conhecidas
+	Allow []string `yaml:"allow"`
+	// AllowPaths são padrões de caminhos que não são verificados, como testdata/*
+	AllowPaths []string `yaml:"allow_paths"`
+	// DisabledRules são os nomes das regras desativadas
+	DisabledRules []string `yaml:"disabled_rules"`
+}
+
 type Config struct {
 	GeminiAPIKey string
 	HomeDir      string
 	PluginsDir   string
+	Model        string
+	Profile      string
+	Prices       map[string]ModelPrice
+	CacheDir     string
+	CacheTTL     time.Duration
+	PromptsDir   string
+	LanguagesDir string
+	// TemplatesDir guarda os arquivos copiados pelas entradas copy-from-template do manifesto
+	TemplatesDir string
+	Lang         string
+	DocLang      string
+	// Registries mapeia cada ecossistema (npm, go, pypi, cargo) para a URL do registro ou
+	// para um diretório local com a mesma estrutura
+	Registries map[string]string
+	// JSONStyles define a indentação dos arquivos JSON por nome de arquivo (package.json)
+	// ou por extensão (.json)
+	JSONStyles map[string]JSONStyle
+	// Formatters são os formatadores externos (prettier, black, rustfmt) usados por zion
+	// format, quando instalados
+	Formatters []string
+	// Secrets configura a verificação de segredos antes da gravação dos arquivos gerados
+	Secrets SecretsConfig
+	// Err é o erro de leitura ou de parse do config.yaml; nesse caso os valores do arquivo
+	// são ignorados e valem os padrões
+	Err error
+}
+
+// fileConfig espelha o arquivo config.yaml opcional do diretório home do Zion
+type fileConfig struct {
+	GeminiAPIKey string                `yaml:"gemini_api_key"`
+	PluginsDir   string                `yaml:"plugins_dir"`
+	Model        string                `yaml:"model"`
+	Profile      string                `yaml:"profile"`
+	Prices       map[string]ModelPrice `yaml:"prices"`
+	CacheTTL     string                `yaml:"cache_ttl"`
+	Lang         string                `yaml:"lang"`
+	DocLang      string                `yaml:"doc_lang"`
+	Registries   map[string]string     `yaml:"registries"`
+	JSONStyles   map[string]JSONStyle  `yaml:"json_style"`
+	Formatters   []string              `yaml:"formatters"`
+	Secrets      SecretsConfig         `yaml:"secrets"`
+}
+
+// JSONStyleFor retorna o estilo de um arquivo JSON: o do nome do arquivo, o da extensão
+// ou, na falta dos dois, o de ".json"
+func (c *Config) JSONStyleFor(path string) JSONStyle {
+	base := filepath.Base(filepath.FromSlash(path))
+	if style, ok := c.JSONStyles[base]; ok {
+		return style
+	}
+	if style, ok := c.JSONStyles[filepath.Ext(base)]; ok {
+		return style
+	}
+	if style, ok := c.JSONStyles[".json"]; ok {
+		return style
+	}
+	return DefaultJSONStyles[".json"]
 }
 
 func LoadConfig() *Config {
@@ -20,7 +160,7 @@ func LoadConfig() *Config {
 
 	// Definir o diretório .zion dentro do home
 	zionDir := filepath.Join(homeDir, ".zion")
-	
+
 	// Garantir que o diretório .zion existe
 	if _, err := os.Stat(zionDir); os.IsNotExist(err) {
 		os.MkdirAll(zionDir, 0755)
@@ -28,15 +168,94 @@ func LoadConfig() *Config {
 
 	// Definir o diretório de plugins dentro de .zion
 	pluginsDir := filepath.Join(zionDir, "plugins")
-	
+
 	// Garantir que o diretório de plugins existe
 	if _, err := os.Stat(pluginsDir); os.IsNotExist(err) {
 		os.MkdirAll(pluginsDir, 0755)
 	}
 
-	return &Config{
+	cfg := &Config{
 		GeminiAPIKey: os.Getenv("GEMINI_API_KEY"),
 		HomeDir:      zionDir,
 		PluginsDir:   pluginsDir,
+		Model:        DefaultModel,
+		Profile:      DefaultProfile,
+		Prices:       make(map[string]ModelPrice),
+		CacheDir:     filepath.Join(zionDir, "cache"),
+		CacheTTL:     DefaultCacheTTL,
+		PromptsDir:   filepath.Join(zionDir, "prompts"),
+		LanguagesDir: filepath.Join(zionDir, "languages"),
+		TemplatesDir: filepath.Join(zionDir, "templates"),
+		Registries:   make(map[string]string),
+		JSONStyles:   make(map[string]JSONStyle),
+	}
+	for model, price := range DefaultPrices {
+		cfg.Prices[model] = price
+	}
+	for ecosystem, endpoint := range DefaultRegistries {
+		cfg.Registries[ecosystem] = endpoint
 	}
+	for key, style := range DefaultJSONStyles {
+		cfg.JSONStyles[key] = style
+	}
+
+	// Aplicar o arquivo config.yaml, se existir
+	data, err := os.ReadFile(filepath.Join(zionDir, "config.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		cfg.Err = fmt.Errorf("erro ao ler config.yaml: %v", err)
+	}
+	if err == nil {
+		var fc fileConfig
+		if err := yaml.Unmarshal(data, &fc); err != nil {
+			cfg.Err = fmt.Errorf("erro ao interpretar config.yaml: %v", err)
+		} else {
+			if cfg.GeminiAPIKey == "" {
+				cfg.GeminiAPIKey = fc.GeminiAPIKey
+			}
+			if fc.PluginsDir != "" {
+				cfg.PluginsDir = fc.PluginsDir
+			}
+			if fc.Model != "" {
+				cfg.Model = fc.Model
+			}
+			if fc.Profile != "" {
+				cfg.Profile = fc.Profile
+			}
+			for model, price := range fc.Prices {
+				cfg.Prices[model] = price
+			}
+			if ttl, err := time.ParseDuration(fc.CacheTTL); err == nil {
+				cfg.CacheTTL = ttl
+			}
+			cfg.Lang = fc.Lang
+			cfg.DocLang = fc.DocLang
+			for ecosystem, endpoint := range fc.Registries {
+				cfg.Registries[ecosystem] = endpoint
+			}
+			for key, style := range fc.JSONStyles {
+				cfg.JSONStyles[key] = style
+			}
+			cfg.Formatters = fc.Formatters
+			cfg.Secrets = fc.Secrets
+		}
+	}
+
+	// Variáveis de ambiente têm precedência sobre o arquivo
+	if model := os.Getenv("GEMINI_MODEL"); model != "" {
+		cfg.Model = model
+	}
+	if profile := os.Getenv("ZION_PROFILE"); profile != "" {
+		cfg.Profile = profile
+	}
+	if ttl, err := time.ParseDuration(os.Getenv("ZION_CACHE_TTL")); err == nil {
+		cfg.CacheTTL = ttl
+	}
+	if lang := os.Getenv("ZION_LANG"); lang != "" {
+		cfg.Lang = lang
+	}
+	if docLang := os.Getenv("ZION_DOC_LANG"); docLang != "" {
+		cfg.DocLang = docLang
+	}
+
+	return cfg
 }
@@ -2,7 +2,12 @@ module github.com/ktfth/zion
 
 go 1.20
 
-require github.com/spf13/cobra v1.6.1
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/spf13/cobra v1.6.1
+	golang.org/x/mod v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.0.1 // indirect
@@ -0,0 +1,157 @@
+// Package i18n traduz as mensagens da CLI e os documentos gerados pelo Zion.
+//
+// As mensagens ficam em catálogos JSON (chave -> formato do fmt) embutidos no binário.
+// Arquivos <idioma>.json em ~/.zion/i18n substituem chaves dos catálogos padrão ou
+// acrescentam novos idiomas.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"zion/config"
+)
+
+//go:embed catalogs/*.json docs
+var embedded embed.FS
+
+// DefaultLanguage é o idioma usado quando nenhum outro é configurado
+const DefaultLanguage = "pt"
+
+var (
+	mu       sync.RWMutex
+	current  = DefaultLanguage
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+)
+
+// languageNames são os nomes usados nos prompts para pedir documentação em um idioma
+var languageNames = map[string]string{
+	"pt": "português do Brasil",
+	"en": "inglês (English)",
+	"es": "espanhol (español)",
+	"fr": "francês (français)",
+	"de": "alemão (Deutsch)",
+	"it": "italiano (italiano)",
+}
+
+// Normalize reduz identificadores como "en_US.UTF-8" ou "pt-BR" ao código do idioma
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// loadCatalogs carrega os catálogos embutidos e as substituições de ~/.zion/i18n
+func loadCatalogs() {
+	catalogs = make(map[string]map[string]string)
+
+	entries, _ := embedded.ReadDir("catalogs")
+	for _, entry := range entries {
+		data, err := embedded.ReadFile(path.Join("catalogs", entry.Name()))
+		if err == nil {
+			mergeCatalog(strings.TrimSuffix(entry.Name(), ".json"), data)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(config.LoadConfig().HomeDir, "i18n", "*.json"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if !mergeCatalog(strings.TrimSuffix(filepath.Base(file), ".json"), data) {
+			fmt.Fprintf(os.Stderr, "⚠️  Catálogo de mensagens inválido ignorado: %s\n", file)
+		}
+	}
+}
+
+// mergeCatalog acrescenta as mensagens de um arquivo ao catálogo do idioma
+func mergeCatalog(lang string, data []byte) bool {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return false
+	}
+	lang = Normalize(lang)
+	if catalogs[lang] == nil {
+		catalogs[lang] = make(map[string]string)
+	}
+	for key, message := range messages {
+		catalogs[lang][key] = message
+	}
+	return true
+}
+
+// Languages lista os idiomas com catálogo disponível
+func Languages() []string {
+	loadOnce.Do(loadCatalogs)
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetLanguage define o idioma das mensagens da CLI
+func SetLanguage(lang string) error {
+	loadOnce.Do(loadCatalogs)
+	lang = Normalize(lang)
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("idioma '%s' não suportado (disponíveis: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+	return nil
+}
+
+// Language retorna o idioma atual das mensagens da CLI
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T retorna a mensagem da chave no idioma atual, formatada com os argumentos.
+// Chaves ausentes no idioma atual usam o catálogo padrão e, por fim, a própria chave.
+func T(key string, args ...interface{}) string {
+	loadOnce.Do(loadCatalogs)
+	message, ok := catalogs[Language()][key]
+	if !ok {
+		if message, ok = catalogs[DefaultLanguage][key]; !ok {
+			message = key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Doc retorna um documento embutido (docs/<idioma>/<nome>) no idioma pedido,
+// recorrendo ao idioma padrão quando não há tradução
+func Doc(name, lang string) string {
+	data, err := embedded.ReadFile(path.Join("docs", Normalize(lang), name))
+	if err != nil {
+		data, _ = embedded.ReadFile(path.Join("docs", DefaultLanguage, name))
+	}
+	return string(data)
+}
+
+// LanguageName retorna o nome do idioma usado nos prompts; códigos desconhecidos
+// são devolvidos como foram informados
+func LanguageName(lang string) string {
+	if name, ok := languageNames[Normalize(lang)]; ok {
+		return name
+	}
+	return lang
+}
@@ -0,0 +1,258 @@
+// Package languages carrega os pacotes de linguagem: descrições declarativas de cada
+// linguagem e de seus frameworks, com o trecho de prompt, os arquivos esperados no
+// projeto, os comandos pós-criação e de instalação, os padrões do .gitignore, os
+// validadores aplicáveis e os comandos de verificação.
+//
+// Os pacotes padrão são embutidos no binário; arquivos JSON em ~/.zion/languages ou em
+// .zion/languages no diretório do projeto acrescentam linguagens ou substituem as
+// existentes com o mesmo nome.
+package languages
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"zion/config"
+)
+
+//go:embed packs/*.json
+var defaultPacks embed.FS
+
+// OriginDefault identifica um pacote embutido no binário
+const OriginDefault = "padrão"
+
+// Framework descreve um framework disponível para uma linguagem
+type Framework struct {
+	Name          string   `json:"name"`
+	DisplayName   string   `json:"display_name"`
+	Aliases       []string `json:"aliases"`
+	Prompt        string   `json:"prompt"`
+	ExpectedFiles []string `json:"expected_files"`
+	PostCreate    []string `json:"post_create"`
+	Install       []string `json:"install"`
+	Gitignore     []string `json:"gitignore"`
+	Verify        []string `json:"verify"`
+}
+
+// Pack descreve uma linguagem
+type Pack struct {
+	Name          string      `json:"name"`
+	DisplayName   string      `json:"display_name"`
+	Aliases       []string    `json:"aliases"`
+	Prompt        string      `json:"prompt"`
+	ExpectedFiles []string    `json:"expected_files"`
+	PostCreate    []string    `json:"post_create"`
+	Install       []string    `json:"install"`
+	Gitignore     []string    `json:"gitignore"`
+	Validators    []string    `json:"validators"`
+	Verify        []string    `json:"verify"`
+	Frameworks    []Framework `json:"frameworks"`
+
+	// Origin é OriginDefault ou o caminho do arquivo de onde o pacote foi carregado
+	Origin string `json:"-"`
+}
+
+// Registry reúne os pacotes de linguagem disponíveis
+type Registry struct {
+	packs map[string]*Pack
+}
+
+// Load carrega os pacotes padrão e aplica os do diretório do usuário e, se projectDir
+// for informado, os de projectDir/.zion/languages
+func Load(projectDir string) (*Registry, error) {
+	registry := &Registry{packs: make(map[string]*Pack)}
+
+	err := fs.WalkDir(defaultPacks, "packs", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := defaultPacks.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return registry.add(data, OriginDefault)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar pacotes de linguagem padrão: %v", err)
+	}
+
+	dirs := []string{config.LoadConfig().LanguagesDir}
+	if projectDir != "" {
+		dirs = append(dirs, filepath.Join(projectDir, ".zion", "languages"))
+	}
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler pacote de linguagem %s: %v", path, err)
+			}
+			if err := registry.add(data, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return registry, nil
+}
+
+// add decodifica um pacote e o registra, substituindo um pacote anterior com o mesmo nome
+func (r *Registry) add(data []byte, origin string) error {
+	var pack Pack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return fmt.Errorf("erro ao ler pacote de linguagem %s: %v", origin, err)
+	}
+	if pack.Name == "" {
+		return fmt.Errorf("pacote de linguagem %s sem nome", origin)
+	}
+	pack.Name = strings.ToLower(pack.Name)
+	if pack.DisplayName == "" {
+		pack.DisplayName = pack.Name
+	}
+	pack.Origin = origin
+	r.packs[pack.Name] = &pack
+	return nil
+}
+
+// Packs retorna os pacotes em ordem alfabética de nome
+func (r *Registry) Packs() []*Pack {
+	packs := make([]*Pack, 0, len(r.packs))
+	for _, pack := range r.packs {
+		packs = append(packs, pack)
+	}
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs
+}
+
+// Find procura um pacote pelo nome ou por um de seus apelidos; retorna nil se não houver
+func (r *Registry) Find(language string) *Pack {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if pack, ok := r.packs[language]; ok {
+		return pack
+	}
+	for _, pack := range r.packs {
+		if matchesName(language, pack.Name, pack.Aliases) {
+			return pack
+		}
+	}
+	return nil
+}
+
+// Detect identifica a linguagem de um projeto existente pelo pacote cujos arquivos
+// esperados estão todos presentes em dir, preferindo o que exige mais arquivos.
+// Retorna nil quando nenhum pacote corresponde.
+func (r *Registry) Detect(dir string) *Pack {
+	var best *Pack
+	for _, pack := range r.Packs() {
+		if len(pack.ExpectedFiles) == 0 || (best != nil && len(pack.ExpectedFiles) <= len(best.ExpectedFiles)) {
+			continue
+		}
+		found := true
+		for _, file := range pack.ExpectedFiles {
+			if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+				found = false
+				break
+			}
+		}
+		if found {
+			best = pack
+		}
+	}
+	return best
+}
+
+// Framework procura um framework do pacote pelo nome ou apelido
+func (p *Pack) Framework(name string) (*Framework, error) {
+	for i := range p.Frameworks {
+		framework := &p.Frameworks[i]
+		if matchesName(strings.ToLower(strings.TrimSpace(name)), framework.Name, framework.Aliases) {
+			return framework, nil
+		}
+	}
+	return nil, fmt.Errorf("framework '%s' não disponível para %s (disponíveis: %s)", name, p.DisplayName, strings.Join(p.FrameworkNames(), ", "))
+}
+
+// FrameworkNames lista os nomes dos frameworks do pacote
+func (p *Pack) FrameworkNames() []string {
+	names := make([]string, 0, len(p.Frameworks))
+	for _, framework := range p.Frameworks {
+		names = append(names, framework.Name)
+	}
+	return names
+}
+
+// ExpectedFilesFor retorna os arquivos esperados da linguagem e do framework, sem repetições
+func (p *Pack) ExpectedFilesFor(framework *Framework) []string {
+	files := append([]string(nil), p.ExpectedFiles...)
+	if framework != nil {
+		files = append(files, framework.ExpectedFiles...)
+	}
+	return unique(files)
+}
+
+// PostCreateFor retorna os comandos pós-criação da linguagem e do framework, sem repetições
+func (p *Pack) PostCreateFor(framework *Framework) []string {
+	commands := append([]string(nil), p.PostCreate...)
+	if framework != nil {
+		commands = append(commands, framework.PostCreate...)
+	}
+	return unique(commands)
+}
+
+// InstallFor retorna os comandos de instalação de dependências da linguagem e do framework
+func (p *Pack) InstallFor(framework *Framework) []string {
+	commands := append([]string(nil), p.Install...)
+	if framework != nil {
+		commands = append(commands, framework.Install...)
+	}
+	return unique(commands)
+}
+
+// GitignoreFor retorna os padrões do .gitignore da linguagem e do framework
+func (p *Pack) GitignoreFor(framework *Framework) []string {
+	patterns := append([]string(nil), p.Gitignore...)
+	if framework != nil {
+		patterns = append(patterns, framework.Gitignore...)
+	}
+	return unique(patterns)
+}
+
+// VerifyFor retorna os comandos de verificação (build, checagem de tipos) da linguagem e do framework
+func (p *Pack) VerifyFor(framework *Framework) []string {
+	commands := append([]string(nil), p.Verify...)
+	if framework != nil {
+		commands = append(commands, framework.Verify...)
+	}
+	return unique(commands)
+}
+
+// matchesName compara um nome já normalizado com o nome e os apelidos informados
+func matchesName(name, canonical string, aliases []string) bool {
+	if name == strings.ToLower(canonical) {
+		return true
+	}
+	for _, alias := range aliases {
+		if name == strings.ToLower(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// unique remove itens repetidos preservando a ordem
+func unique(items []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range items {
+		if seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
@@ -9,6 +9,11 @@ func (p CorePlugin) Name() string {
 	return "CorePlugin"
 }
 
+// Version retorna a versão do plugin
+func (p CorePlugin) Version() string {
+	return "1.0.0"
+}
+
 func (p CorePlugin) Execute() error {
 	fmt.Println("CorePlugin executado com sucesso!")
 	return nil
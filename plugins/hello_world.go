@@ -14,6 +14,11 @@ func (p *HelloWorldPlugin) Name() string {
 	return "HelloWorld"
 }
 
+// Version retorna a versão do plugin
+func (p *HelloWorldPlugin) Version() string {
+	return "1.0.0"
+}
+
 // Execute é chamado quando o plugin é executado
 func (p *HelloWorldPlugin) Execute() error {
 	fmt.Println("HelloWorld plugin: Olá, mundo!")
@@ -31,6 +31,13 @@ type Plugin interface {
 	GetHooks() map[ScaffoldHook]interface{}
 }
 
+// VersionedPlugin é implementado pelos plugins que informam a própria versão
+type VersionedPlugin interface {
+	Plugin
+	// Version retorna a versão do plugin.
+	Version() string
+}
+
 // ScaffoldContext contém informações sobre o processo de geração de scaffold
 type ScaffoldContext struct {
 	ProjectName string
@@ -46,7 +53,7 @@ var registeredPlugins = make(map[string]Plugin)
 // RegisterPlugin permite o registro de um plugin.
 func RegisterPlugin(p Plugin) {
 	registeredPlugins[p.Name()] = p
-	fmt.Printf("Plugin registrado: %s\n", p.Name())
+	fmt.Fprintf(os.Stderr, "Plugin registrado: %s\n", p.Name())
 }
 
 // ListPlugins retorna os nomes dos plugins registrados.
@@ -58,6 +65,20 @@ func ListPlugins() []string {
 	return names
 }
 
+// PluginVersions retorna a versão de cada plugin registrado. Plugins que não
+// implementam VersionedPlugin aparecem com a versão vazia.
+func PluginVersions() map[string]string {
+	versions := make(map[string]string, len(registeredPlugins))
+	for name, p := range registeredPlugins {
+		if versioned, ok := p.(VersionedPlugin); ok {
+			versions[name] = versioned.Version()
+		} else {
+			versions[name] = ""
+		}
+	}
+	return versions
+}
+
 // ExecutePlugins executa a função Execute de cada plugin registrado.
 func ExecutePlugins() {
 	for name, plugin := range registeredPlugins {
@@ -97,12 +118,12 @@ func LoadPlugins() error {
 		pluginPath := filepath.Join(pluginsDir, entry.Name())
 		p, err := loadPlugin(pluginPath)
 		if err != nil {
-			fmt.Printf("⚠️  Aviso: erro ao carregar plugin %s: %v\n", entry.Name(), err)
+			fmt.Fprintf(os.Stderr, "⚠️  Aviso: erro ao carregar plugin %s: %v\n", entry.Name(), err)
 			continue
 		}
 
 		registeredPlugins[p.Name()] = p
-		fmt.Printf("✅ Plugin carregado: %s\n", p.Name())
+		fmt.Fprintf(os.Stderr, "✅ Plugin carregado: %s\n", p.Name())
 	}
 
 	return nil
@@ -0,0 +1,171 @@
+// Package prompts carrega os templates de prompt usados na geração e na alteração de projetos.
+// Os templates padrão são embutidos no binário e podem ser substituídos por arquivos
+// com o mesmo nome em ~/.zion/prompts ou em .zion/prompts no diretório do projeto.
+package prompts
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"zion/config"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// OriginDefault identifica um template embutido no binário
+const OriginDefault = "padrão"
+
+// Data são os valores disponíveis nos templates
+type Data struct {
+	Language    string
+	Framework   string
+	ProjectName string
+	Description string
+	// LanguageRequirements é o trecho de prompt do pacote da linguagem
+	LanguageRequirements string
+	// FrameworkRequirements é o trecho de prompt do framework escolhido
+	FrameworkRequirements string
+	// DocLanguage é o nome do idioma da documentação e dos comentários gerados
+	DocLanguage string
+	// ProjectDescription é o template project.tmpl já renderizado
+	ProjectDescription string
+
+	// Tree é a árvore planejada do projeto, usada em outline_file.tmpl
+	Tree string
+	// FilePath e FilePurpose descrevem o arquivo pedido em outline_file.tmpl
+	FilePath    string
+	FilePurpose string
+	// CompletedFiles são os arquivos já gerados antes de uma resposta truncada
+	CompletedFiles []string
+	// ProjectContext são a árvore e o conteúdo selecionado de um projeto existente
+	ProjectContext string
+	// Problems são os problemas que fix.tmpl pede para corrigir
+	Problems string
+	// Feature é a funcionalidade pedida em feature.tmpl
+	Feature string
+	// Instruction é a alteração pedida em refine.tmpl
+	Instruction string
+}
+
+// Set é o conjunto de templates disponíveis, já com as substituições aplicadas
+type Set struct {
+	sources map[string]string
+	origins map[string]string
+}
+
+// Load carrega os templates padrão e aplica as substituições do diretório do usuário
+// e, se projectDir for informado, as de projectDir/.zion/prompts
+func Load(projectDir string) (*Set, error) {
+	set := &Set{sources: make(map[string]string), origins: make(map[string]string)}
+
+	err := fs.WalkDir(defaultTemplates, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := defaultTemplates.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(path, "templates/")
+		set.sources[name] = string(data)
+		set.origins[name] = OriginDefault
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar templates padrão: %v", err)
+	}
+
+	dirs := []string{config.LoadConfig().PromptsDir}
+	if projectDir != "" {
+		dirs = append(dirs, filepath.Join(projectDir, ".zion", "prompts"))
+	}
+	for _, dir := range dirs {
+		if err := set.overlay(dir); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// overlay substitui ou acrescenta os templates .tmpl encontrados em dir
+func (s *Set) overlay(dir string) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".tmpl" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("erro ao ler template %s: %v", path, err)
+		}
+		name := filepath.ToSlash(rel)
+		s.sources[name] = string(data)
+		s.origins[name] = path
+		return nil
+	})
+}
+
+// Names lista os templates disponíveis em ordem alfabética
+func (s *Set) Names() []string {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Origin informa de onde o template foi carregado: OriginDefault ou o caminho do arquivo
+func (s *Set) Origin(name string) string {
+	return s.origins[name]
+}
+
+// Has indica se existe um template com o nome informado
+func (s *Set) Has(name string) bool {
+	_, ok := s.sources[name]
+	return ok
+}
+
+// Render executa o template informado com os dados fornecidos
+func (s *Set) Render(name string, data Data) (string, error) {
+	source, ok := s.sources[name]
+	if !ok {
+		return "", fmt.Errorf("template %s não encontrado", name)
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("erro no template %s (%s): %v", name, s.origins[name], err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("erro ao renderizar template %s (%s): %v", name, s.origins[name], err)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// ProjectDescription renderiza a descrição do projeto
+func (s *Set) ProjectDescription(data Data) (string, error) {
+	return s.Render("project.tmpl", data)
+}
+
+// Scaffold renderiza o prompt completo de geração do scaffold
+func (s *Set) Scaffold(data Data) (string, error) {
+	description, err := s.ProjectDescription(data)
+	if err != nil {
+		return "", err
+	}
+	data.ProjectDescription = description
+	return s.Render("scaffold.tmpl", data)
+}